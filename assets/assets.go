@@ -3,74 +3,185 @@ package assets
 import (
 	"bytes"
 	"embed"
+	"errors"
+	"fmt"
 	"image"
-
-	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+	"image/draw"
+	_ "image/png"
+	"io/fs"
+	"slices"
 )
 
 //go:embed *
 var assets embed.FS
 
+// Sprites and sounds, filled in by Load. Reload updates sprites and sheets in
+// place, so code holding one of these pointers always sees the current pixels.
 var (
-	topInvaderSpriteSheet    = loadImage("invaders/topInvader.png")
-	middleInvaderSpriteSheet = loadImage("invaders/middleInvader.png")
-	bottomInvaderSpriteSheet = loadImage("invaders/bottomInvader.png")
-
-	TopInvaderAnimation    = splitImage(topInvaderSpriteSheet)
-	MiddleInvaderAnimation = splitImage(middleInvaderSpriteSheet)
-	BottomInvaderAnimation = splitImage(bottomInvaderSpriteSheet)
-
-	Player     = loadImage("player/Player.png")
-	PlayerShot = loadImage("player/PlayerShot.png")
-	AlienShot  = loadImage("invaders/AlienShot.png")
-	UFO        = loadImage("invaders/ufo.png")
-
-	baseSpriteSheet = loadImage("player/base.png")
-	BaseSprites     = splitBaseImage(baseSpriteSheet)
-
-	MoveSound           = loadAudio("audio/move.ogg")
-	PlayerShootSound    = loadAudio("audio/laserShoot.ogg")
-	AlienExplosionSound = loadAudio("audio/alienexplosion.ogg")
-	PlayerDeathSound    = loadAudio("audio/playerDeath.ogg")
-	UFOSound            = loadAudio("audio/ufo.ogg")
+	TopInvaderAnimation    *Sheet
+	MiddleInvaderAnimation *Sheet
+	BottomInvaderAnimation *Sheet
+
+	Player     *Sprite
+	PlayerShot *Sprite
+	AlienShot  *Sprite
+	UFO        *Sprite
+
+	BaseSprites   *Sheet // Brick shield, one frame per damage level
+	SteelSprites  *Sheet
+	EnergySprites *Sheet
+
+	PlayerShootSound    []byte
+	AlienExplosionSound []byte
+	PlayerDeathSound    []byte
+	UFOSound            []byte
 )
 
-func loadImage(filePath string) *ebiten.Image {
-	data, err := assets.ReadFile(filePath)
-	if err != nil {
-		panic(err)
+// soundFiles maps each sound asset to the variable Load fills in.
+var soundFiles = map[string]*[]byte{
+	"audio/laserShoot.ogg":     &PlayerShootSound,
+	"audio/alienexplosion.ogg": &AlienExplosionSound,
+	"audio/playerDeath.ogg":    &PlayerDeathSound,
+	"audio/ufo.ogg":            &UFOSound,
+}
+
+// Sprite is a single decoded image and its collision mask. The image's
+// bounds start at 0, 0.
+type Sprite struct {
+	Path  string
+	Image *image.RGBA
+	Mask  *Mask // Solid pixels, from the image's alpha
+}
+
+// Bounds returns the sprite's size as a rectangle at 0, 0.
+func (s *Sprite) Bounds() image.Rectangle {
+	return s.Image.Bounds()
+}
+
+// Loaded sprites and sheets by asset path, so Reload and the texture uploader
+// can find them again.
+var (
+	sprites    = make(map[string]*Sprite)
+	sheets     = make(map[string]*sheetSource)
+	imagePaths []string // In load order
+)
+
+// placeholderColor marks sprites that failed to load so they stand out in game.
+var placeholderColor = color.RGBA{255, 0, 255, 255}
+
+// Load decodes every asset. Assets that are missing or invalid are replaced by
+// magenta placeholders or silence, and all of the problems are returned
+// together so they can be fixed in one pass. Release builds should treat a
+// non-nil error as fatal; dev builds can run on the placeholders.
+//
+// Load only decodes; it needs no graphics or audio device, so the leaderboard
+// server can load the same rules the game plays by.
+func Load() error {
+	l := &loader{}
+	sprites = make(map[string]*Sprite)
+	sheets = make(map[string]*sheetSource)
+	imagePaths = nil
+
+	// Sheets are cut by their .json sidecar if they have one, otherwise into 16px frames
+	TopInvaderAnimation = l.sheet("invaders/topInvader.png", 16, 16, 2)
+	MiddleInvaderAnimation = l.sheet("invaders/middleInvader.png", 16, 16, 2)
+	BottomInvaderAnimation = l.sheet("invaders/bottomInvader.png", 16, 16, 2)
+
+	Player = l.sprite("player/Player.png", 16, 16)
+	PlayerShot = l.sprite("player/PlayerShot.png", 16, 16)
+	AlienShot = l.sprite("invaders/AlienShot.png", 4, 16)
+	UFO = l.sprite("invaders/ufo.png", 16, 16)
+
+	BaseSprites = l.sheet("player/base.png", 16, 16, 3)
+	SteelSprites = l.sheet("player/steel.png", 16, 16, 3)
+	EnergySprites = l.sheet("player/energy.png", 16, 16, 3)
+
+	ShieldLayouts = l.shieldLayouts()
+
+	for filePath, sound := range soundFiles {
+		*sound = l.audio(filePath)
 	}
 
-	img, _, err := image.Decode(bytes.NewReader(data))
+	return errors.Join(l.errs...)
+}
+
+// ImagePaths lists every sprite and sheet Load decoded, in load order.
+func ImagePaths() []string {
+	return slices.Clone(imagePaths)
+}
+
+// Image returns the decoded pixels of a sprite or sheet, or nil if Load
+// didn't decode the path.
+func Image(filePath string) *image.RGBA {
+	if sprite, ok := sprites[filePath]; ok {
+		return sprite.Image
+	}
+	if src, ok := sheets[filePath]; ok {
+		return src.sheet.Image
+	}
+	return nil
+}
+
+// loader collects every load failure instead of stopping at the first.
+type loader struct {
+	errs []error
+}
+
+// decode reads an image, falling back to a placeholder of the expected size.
+func (l *loader) decode(filePath string, width, height int) *image.RGBA {
+	img, err := decodeImage(filePath)
 	if err != nil {
-		panic(err)
+		l.errs = append(l.errs, fmt.Errorf("%s: %w", filePath, err))
+		img = image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(img, img.Bounds(), image.NewUniform(placeholderColor), image.Point{}, draw.Src)
 	}
+	imagePaths = append(imagePaths, filePath)
+	return img
+}
 
-	ebitenImg := ebiten.NewImageFromImage(img)
-	return ebitenImg
+// sprite loads a single-image sprite.
+func (l *loader) sprite(filePath string, width, height int) *Sprite {
+	img := l.decode(filePath, width, height)
+	sprite := &Sprite{Path: filePath, Image: img, Mask: NewMask(img)}
+	sprites[filePath] = sprite
+	return sprite
 }
 
-func loadAudio(filePath string) []byte {
-	data, err := assets.ReadFile(filePath)
+// audio loads a sound, falling back to nil which plays as silence.
+func (l *loader) audio(filePath string) []byte {
+	data, err := loadAudio(filePath)
 	if err != nil {
-		panic(err)
+		l.errs = append(l.errs, fmt.Errorf("%s: %w", filePath, err))
+		return nil
 	}
 	return data
 }
 
-func splitImage(spriteSheet *ebiten.Image) []*ebiten.Image {
-	const invaderSize = 16
+// decodeImage reads a PNG into RGBA pixels whose bounds start at 0, 0.
+func decodeImage(filePath string) (*image.RGBA, error) {
+	data, err := fs.ReadFile(source, filePath)
+	if err != nil {
+		return nil, err
+	}
 
-	firstFrame := spriteSheet.SubImage(image.Rect(0, 0, invaderSize, invaderSize)).(*ebiten.Image)
-	secondFrame := spriteSheet.SubImage(image.Rect(invaderSize, 0, invaderSize*2, invaderSize)).(*ebiten.Image)
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
 
-	return []*ebiten.Image{firstFrame, secondFrame}
+	rgba := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba, nil
 }
 
-func splitBaseImage(spriteSheet *ebiten.Image) []*ebiten.Image {
-	const tileSize = 16
-	first := spriteSheet.SubImage(image.Rect(0, 0, tileSize, tileSize)).(*ebiten.Image)
-	second := spriteSheet.SubImage(image.Rect(tileSize, 0, tileSize*2, tileSize)).(*ebiten.Image)
-	third := spriteSheet.SubImage(image.Rect(tileSize*2, 0, tileSize*3, tileSize)).(*ebiten.Image)
-	return []*ebiten.Image{first, second, third}
+func loadAudio(filePath string) ([]byte, error) {
+	data, err := fs.ReadFile(source, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte("OggS")) {
+		return nil, errors.New("not an Ogg file")
+	}
+	return data, nil
 }
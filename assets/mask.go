@@ -0,0 +1,78 @@
+package assets
+
+import (
+	"image"
+)
+
+// maskAlphaThreshold is the alpha at or above which a pixel counts as solid.
+const maskAlphaThreshold = 0x80
+
+// Mask records which pixels of a sprite are solid, for pixel-accurate collisions.
+type Mask struct {
+	Width  int
+	Height int
+	solid  []bool
+}
+
+// NewMask builds a mask from the alpha of a whole image.
+func NewMask(img image.Image) *Mask {
+	return newMask(img, img.Bounds().Sub(img.Bounds().Min))
+}
+
+// newMask builds a mask from rect of img, relative to the image's top-left.
+func newMask(img image.Image, rect image.Rectangle) *Mask {
+	m := &Mask{
+		Width:  rect.Dx(),
+		Height: rect.Dy(),
+		solid:  make([]bool, rect.Dx()*rect.Dy()),
+	}
+
+	origin := img.Bounds().Min.Add(rect.Min)
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			_, _, _, a := img.At(origin.X+x, origin.Y+y).RGBA()
+			m.solid[y*m.Width+x] = a>>8 >= maskAlphaThreshold
+		}
+	}
+	return m
+}
+
+// Solid reports whether the pixel at x, y is solid. Pixels outside the mask are not.
+func (m *Mask) Solid(x, y int) bool {
+	if x < 0 || y < 0 || x >= m.Width || y >= m.Height {
+		return false
+	}
+	return m.solid[y*m.Width+x]
+}
+
+// Bounds returns the mask's area with its top-left at pos.
+func (m *Mask) Bounds(pos image.Point) image.Rectangle {
+	return image.Rect(0, 0, m.Width, m.Height).Add(pos)
+}
+
+// Overlaps reports whether any solid pixel of m, placed at pos, lies on a solid
+// pixel of other placed at otherPos.
+func (m *Mask) Overlaps(pos image.Point, other *Mask, otherPos image.Point) bool {
+	overlap := m.Bounds(pos).Intersect(other.Bounds(otherPos))
+	for y := overlap.Min.Y; y < overlap.Max.Y; y++ {
+		for x := overlap.Min.X; x < overlap.Max.X; x++ {
+			if m.Solid(x-pos.X, y-pos.Y) && other.Solid(x-otherPos.X, y-otherPos.Y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OverlapsRect reports whether any solid pixel of m, placed at pos, lies inside rect.
+func (m *Mask) OverlapsRect(pos image.Point, rect image.Rectangle) bool {
+	overlap := m.Bounds(pos).Intersect(rect)
+	for y := overlap.Min.Y; y < overlap.Max.Y; y++ {
+		for x := overlap.Min.X; x < overlap.Max.X; x++ {
+			if m.Solid(x-pos.X, y-pos.Y) {
+				return true
+			}
+		}
+	}
+	return false
+}
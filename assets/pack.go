@@ -0,0 +1,264 @@
+package assets
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ManifestName is the file every content pack must have at its root.
+const ManifestName = "pack.json"
+
+// Manifest describes a content pack. Any other file in the pack replaces the
+// embedded asset at the same path, e.g. invaders/ufo.png.
+type Manifest struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Author      string `json:"author"`
+	Description string `json:"description"`
+}
+
+// source is where Load reads assets from: the embedded files, optionally
+// overlaid by a content pack.
+var source fs.FS = assets
+
+// packID identifies the content pack in use, set by UsePack.
+var packID string
+
+// PackID identifies the content pack assets are loaded from by its name,
+// version and a hash of its files, or returns "" for the built-in assets.
+// A pack can change the rules, so replays record which one they were played with.
+func PackID() string {
+	return packID
+}
+
+// UsePack overlays a content pack directory or .zip on the embedded assets.
+// The pack is validated up front and every problem is reported together.
+// Call it before Load.
+func UsePack(packPath string) (*Manifest, error) {
+	pack, err := openPack(packPath)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := ValidatePack(pack)
+	if err != nil {
+		return nil, fmt.Errorf("content pack %s: %w", packPath, err)
+	}
+
+	hash, err := hashPack(pack)
+	if err != nil {
+		return nil, fmt.Errorf("content pack %s: %w", packPath, err)
+	}
+
+	source = overlayFS{overlay: pack, base: assets}
+	packID = fmt.Sprintf("%s %s %x", manifest.Name, manifest.Version, hash[:8])
+	return manifest, nil
+}
+
+// hashPack hashes the path and contents of every file in a pack.
+func hashPack(pack fs.FS) ([]byte, error) {
+	hash := sha256.New()
+	err := fs.WalkDir(pack, ".", func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(pack, filePath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s %d\n", filePath, len(data))
+		hash.Write(data)
+		return nil
+	})
+	return hash.Sum(nil), err
+}
+
+func openPack(packPath string) (fs.FS, error) {
+	info, err := os.Stat(packPath)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return os.DirFS(packPath), nil
+	}
+	if strings.EqualFold(path.Ext(packPath), ".zip") {
+		// The reader stays open for the life of the game
+		return zip.OpenReader(packPath)
+	}
+	return nil, fmt.Errorf("content pack %s must be a directory or .zip", packPath)
+}
+
+// ValidatePack checks a pack's manifest and that every file overrides a known
+// asset with data of the right kind.
+func ValidatePack(pack fs.FS) (*Manifest, error) {
+	var errs []error
+
+	manifest := &Manifest{}
+	data, err := fs.ReadFile(pack, ManifestName)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("reading manifest: %w", err))
+	} else if err := json.Unmarshal(data, manifest); err != nil {
+		errs = append(errs, fmt.Errorf("parsing manifest: %w", err))
+	} else if manifest.Name == "" {
+		errs = append(errs, errors.New("manifest has no name"))
+	}
+
+	err = fs.WalkDir(pack, ".", func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filePath == ManifestName || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		if err := validatePackFile(pack, filePath); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filePath, err))
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return manifest, errors.Join(errs...)
+}
+
+func validatePackFile(pack fs.FS, filePath string) error {
+	if path.Ext(filePath) == ".json" {
+		return validatePackSidecar(pack, filePath)
+	}
+	if ok, _ := path.Match(shieldLayoutPattern, filePath); ok {
+		return validatePackShieldLayout(pack, filePath)
+	}
+
+	if _, err := fs.Stat(assets, filePath); err != nil {
+		return errors.New("does not replace a known asset")
+	}
+
+	data, err := fs.ReadFile(pack, filePath)
+	if err != nil {
+		return err
+	}
+
+	switch path.Ext(filePath) {
+	case ".png":
+		config, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		// A sidecar describes the new layout; without one the sheet is
+		// sliced like the original, so sizes must match
+		if _, err := fs.Stat(pack, sidecarPath(filePath)); err == nil {
+			return nil
+		}
+		original, err := embeddedImageConfig(filePath)
+		if err != nil {
+			return err
+		}
+		if config.Width != original.Width || config.Height != original.Height {
+			return fmt.Errorf("is %dx%d, want %dx%d", config.Width, config.Height, original.Width, original.Height)
+		}
+	case ".ogg":
+		if !bytes.HasPrefix(data, []byte("OggS")) {
+			return errors.New("not an Ogg file")
+		}
+	default:
+		return errors.New("unsupported file type")
+	}
+	return nil
+}
+
+// validatePackSidecar checks sprite sheet metadata against the sheet it
+// describes, from the pack if it has one and the embedded assets otherwise.
+func validatePackSidecar(pack fs.FS, filePath string) error {
+	imagePath := strings.TrimSuffix(filePath, ".json") + ".png"
+	if _, err := fs.Stat(assets, imagePath); err != nil {
+		return errors.New("is not the sidecar of a known sprite sheet")
+	}
+
+	data, err := fs.ReadFile(pack, filePath)
+	if err != nil {
+		return err
+	}
+	var meta sheetMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return err
+	}
+
+	sheet := overlayFS{overlay: pack, base: assets}
+	imageData, err := fs.ReadFile(sheet, imagePath)
+	if err != nil {
+		return err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(imageData))
+	if err != nil {
+		return err
+	}
+	return validateSheetMeta(meta, image.Rect(0, 0, config.Width, config.Height))
+}
+
+// validatePackShieldLayout checks a shield layout, which may be for a wave
+// that has no embedded layout.
+func validatePackShieldLayout(pack fs.FS, filePath string) error {
+	if _, err := shieldLayoutWave(filePath); err != nil {
+		return err
+	}
+	data, err := fs.ReadFile(pack, filePath)
+	if err != nil {
+		return err
+	}
+	_, err = ParseShieldLayout(bytes.NewReader(data))
+	return err
+}
+
+func embeddedImageConfig(filePath string) (image.Config, error) {
+	data, err := assets.ReadFile(filePath)
+	if err != nil {
+		return image.Config{}, err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	return config, err
+}
+
+// overlayFS serves files from overlay when it has them and base otherwise.
+type overlayFS struct {
+	overlay fs.FS
+	base    fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	file, err := o.overlay.Open(name)
+	if err == nil {
+		return file, nil
+	}
+	return o.base.Open(name)
+}
+
+// Glob matches files in both layers, so a pack can add files such as new
+// shield layouts as well as replace them.
+func (o overlayFS) Glob(pattern string) ([]string, error) {
+	matches, err := fs.Glob(o.base, pattern)
+	if err != nil {
+		return nil, err
+	}
+	overlayMatches, err := fs.Glob(o.overlay, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range overlayMatches {
+		if !slices.Contains(matches, match) {
+			matches = append(matches, match)
+		}
+	}
+	slices.Sort(matches)
+	return matches, nil
+}
@@ -0,0 +1,50 @@
+package assets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackID(t *testing.T) {
+	t.Cleanup(func() {
+		source = assets
+		packID = ""
+	})
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(ManifestName, `{"name": "Fortress", "version": "1.0"}`)
+	writeFile("shields/wave1.txt", "count 2\ncell 4\nshape\n####\n")
+
+	if PackID() != "" {
+		t.Fatalf("PackID = %q before UsePack, want empty", PackID())
+	}
+
+	if _, err := UsePack(dir); err != nil {
+		t.Fatal(err)
+	}
+	first := PackID()
+	if !strings.HasPrefix(first, "Fortress 1.0 ") {
+		t.Errorf("PackID = %q, want the pack's name and version first", first)
+	}
+
+	// Changing any rule changes the ID, even if the manifest doesn't say so
+	writeFile("shields/wave1.txt", "count 3\ncell 4\nshape\n####\n")
+	if _, err := UsePack(dir); err != nil {
+		t.Fatal(err)
+	}
+	if PackID() == first {
+		t.Errorf("PackID stayed %q after a shield layout changed", first)
+	}
+}
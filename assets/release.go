@@ -0,0 +1,7 @@
+//go:build !dev
+
+package assets
+
+// DevBuild is true when built with -tags dev, letting the game launch on
+// placeholder assets.
+const DevBuild = false
@@ -0,0 +1,114 @@
+package assets
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"time"
+)
+
+// Reload re-reads one asset from the current source. Sprites and sheets are
+// updated in place, so everything drawn or collided with them changes
+// immediately; textures must be re-uploaded. A sheet's .json sidecar reloads
+// the sheet. Sounds replace their variable; callers must re-decode them.
+func Reload(filePath string) error {
+	switch path.Ext(filePath) {
+	case ".png":
+		return reloadImage(filePath)
+	case ".json":
+		for sheetPath := range sheets {
+			if sidecarPath(sheetPath) == filePath {
+				return reloadImage(sheetPath)
+			}
+		}
+		return fmt.Errorf("%s: not the sidecar of a known sheet", filePath)
+	case ".ogg":
+		sound, ok := soundFiles[filePath]
+		if !ok {
+			return fmt.Errorf("%s: not a known sound", filePath)
+		}
+		data, err := loadAudio(filePath)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+		*sound = data
+		return nil
+	default:
+		return fmt.Errorf("%s: unsupported file type", filePath)
+	}
+}
+
+func reloadImage(filePath string) error {
+	target := Image(filePath)
+	if target == nil {
+		return fmt.Errorf("%s: not a known image", filePath)
+	}
+
+	img, err := decodeImage(filePath)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	// Collision masks follow the new alpha
+	if sprite, ok := sprites[filePath]; ok {
+		sprite.Image = img
+		sprite.Mask = NewMask(img)
+	}
+	if src, ok := sheets[filePath]; ok {
+		// Sheets are cut again, so resized images and edited sidecars take effect
+		frames, err := cutFrames(filePath, img, src.frameWidth, src.frameHeight, src.frameCount)
+		if err != nil {
+			return err
+		}
+		// Whatever is animating may be showing any of the current frames
+		if len(frames) < len(src.sheet.Frames) {
+			return fmt.Errorf("%s: has %d frames, want at least %d", filePath, len(frames), len(src.sheet.Frames))
+		}
+		src.sheet.Image = img
+		src.sheet.Frames = frames
+	}
+	return nil
+}
+
+// Watcher polls a directory for files that have been added or modified.
+type Watcher struct {
+	fsys     fs.FS
+	modTimes map[string]time.Time
+}
+
+func NewWatcher(fsys fs.FS) (*Watcher, error) {
+	w := &Watcher{fsys: fsys}
+	_, err := w.Poll() // Record the starting state so only later edits are reported
+	return w, err
+}
+
+// Poll returns the slash-separated paths of files changed since the last poll.
+func (w *Watcher) Poll() ([]string, error) {
+	modTimes := make(map[string]time.Time)
+	var changed []string
+
+	err := fs.WalkDir(w.fsys, ".", func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		modTimes[filePath] = info.ModTime()
+		if previous, seen := w.modTimes[filePath]; w.modTimes != nil && (!seen || !previous.Equal(info.ModTime())) {
+			changed = append(changed, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w.modTimes = modTimes
+	return changed, nil
+}
@@ -0,0 +1,66 @@
+package assets
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReloadResizedSheet(t *testing.T) {
+	t.Cleanup(func() {
+		source = assets
+		packID = ""
+		Load()
+	})
+
+	dir := t.TempDir()
+	writeFile := func(name string, content []byte) {
+		t.Helper()
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(ManifestName, []byte(`{"name": "Resized", "version": "1.0"}`))
+	if _, err := UsePack(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := Load(); err != nil {
+		t.Fatal(err)
+	}
+	sheet := TopInvaderAnimation
+
+	// A third frame, added to both the image and its sidecar
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 48, 16))); err != nil {
+		t.Fatal(err)
+	}
+	writeFile("invaders/topInvader.png", encoded.Bytes())
+	writeFile("invaders/topInvader.json", []byte(`{"frames": [
+		{"frame": {"x": 0, "y": 0, "w": 16, "h": 16}},
+		{"frame": {"x": 16, "y": 0, "w": 16, "h": 16}},
+		{"frame": {"x": 32, "y": 0, "w": 16, "h": 16}}]}`))
+	if err := Reload("invaders/topInvader.png"); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if sheet.Image.Bounds().Dx() != 48 || len(sheet.Frames) != 3 {
+		t.Errorf("reloaded sheet is %d wide with %d frames, want 48 with 3", sheet.Image.Bounds().Dx(), len(sheet.Frames))
+	}
+
+	// Editing just the sidecar re-cuts the sheet too, but not into fewer frames
+	writeFile("invaders/topInvader.json", []byte(`{"frames": [{"frame": {"x": 0, "y": 0, "w": 16, "h": 16}}]}`))
+	err := Reload("invaders/topInvader.json")
+	if err == nil || !strings.Contains(err.Error(), "want at least 3") {
+		t.Errorf("Reload of a sidecar with fewer frames = %v, want an error", err)
+	}
+	if len(sheet.Frames) != 3 {
+		t.Errorf("failed reload left %d frames, want the 3 from before", len(sheet.Frames))
+	}
+}
@@ -0,0 +1,241 @@
+package assets
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Shield layouts live in shields/waveN.txt. A layout applies from wave N until
+// a later file takes over, so shields/wave1.txt sets the opening bunkers.
+//
+//	; Comments start with a semicolon
+//	count 4      ; Bases spread evenly across the screen
+//	x 32 112     ; Or the left edge of each base, instead of count
+//	cell 4       ; Pixels per character
+//	gap 8        ; Pixels between the bottom of the bases and the top of the player
+//	shape        ; Every line after this is one row of the bunker
+//	########
+//	##....##
+//
+// In the shape, '.' and ' ' are empty and every other character is a solid
+// cell whose character names its material.
+const shieldLayoutPattern = "shields/wave*.txt"
+
+// firstShieldLayout is the built-in layout for wave 1, used when a content
+// pack's is broken, so every game starts with shields.
+const firstShieldLayout = "shields/wave1.txt"
+
+// PlayfieldWidth is the width of the playfield in pixels, which every layout's
+// bases must fit across.
+const PlayfieldWidth = 320
+
+// ShieldCells are the characters a shape may use for solid cells: brick,
+// steel and energy.
+const ShieldCells = "#SE"
+
+// ShieldLayout describes the bunkers for a wave.
+type ShieldLayout struct {
+	Count int      // Number of bases spread evenly across the screen, when X is empty
+	X     []int    // Left edge of each base
+	Cell  int      // Pixels per shape character
+	Gap   int      // Pixels between the bases and the player
+	Shape []string // One string per row, padded to the same width
+}
+
+// ShieldLayouts holds the layouts by the wave they start at, filled in by Load.
+var ShieldLayouts map[int]*ShieldLayout
+
+// ShieldLayoutFor returns the layout in force at a wave. After Load there is
+// always one for wave 1.
+func ShieldLayoutFor(wave int) *ShieldLayout {
+	start := 0
+	for w := range ShieldLayouts {
+		if w <= wave && w > start {
+			start = w
+		}
+	}
+	return ShieldLayouts[start]
+}
+
+// Width and Height return the size of a base in pixels.
+func (s *ShieldLayout) Width() int  { return len(s.Shape[0]) * s.Cell }
+func (s *ShieldLayout) Height() int { return len(s.Shape) * s.Cell }
+
+// At returns the shape character at a pixel of a base, or 0 where it's empty.
+func (s *ShieldLayout) At(x, y int) rune {
+	if x < 0 || y < 0 || x >= s.Width() || y >= s.Height() {
+		return 0
+	}
+	c := rune(s.Shape[y/s.Cell][x/s.Cell])
+	if c == '.' || c == ' ' {
+		return 0
+	}
+	return c
+}
+
+func ParseShieldLayout(r io.Reader) (*ShieldLayout, error) {
+	layout := &ShieldLayout{Count: 4, Cell: 1, Gap: 8}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	inShape := false
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		if inShape {
+			row := strings.TrimRight(text, " \t\r")
+			if row == "" {
+				continue
+			}
+			for _, c := range row {
+				if c != '.' && c != ' ' && !strings.ContainsRune(ShieldCells, c) {
+					return nil, fmt.Errorf("line %d: unknown cell %q", line, c)
+				}
+			}
+			layout.Shape = append(layout.Shape, row)
+			continue
+		}
+
+		if i := strings.IndexByte(text, ';'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		values := make([]int, 0, len(fields)-1)
+		for _, field := range fields[1:] {
+			v, err := strconv.Atoi(field)
+			if err != nil || v < 0 {
+				return nil, fmt.Errorf("line %d: %q is not a number", line, field)
+			}
+			values = append(values, v)
+		}
+
+		switch key := fields[0]; key {
+		case "shape":
+			inShape = true
+			continue
+		case "x":
+			layout.X = values
+			continue
+		}
+		if len(values) != 1 {
+			return nil, fmt.Errorf("line %d: %s takes one number", line, fields[0])
+		}
+		switch fields[0] {
+		case "count":
+			layout.Count = values[0]
+		case "cell":
+			layout.Cell = values[0]
+		case "gap":
+			layout.Gap = values[0]
+		default:
+			return nil, fmt.Errorf("line %d: unknown setting %q", line, fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(layout.Shape) == 0 {
+		return nil, errors.New("no shape")
+	}
+	if layout.Cell == 0 {
+		return nil, errors.New("cell must be at least 1")
+	}
+
+	// Pad ragged rows so every row is as wide as the widest
+	width := 0
+	for _, row := range layout.Shape {
+		width = max(width, len(row))
+	}
+	for i, row := range layout.Shape {
+		layout.Shape[i] = row + strings.Repeat(".", width-len(row))
+	}
+
+	if err := layout.checkFit(); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// checkFit reports bases that would hang off the playfield.
+func (s *ShieldLayout) checkFit() error {
+	if len(s.X) == 0 {
+		if s.Count*s.Width() > PlayfieldWidth {
+			return fmt.Errorf("%d bases %dpx wide don't fit across the %dpx playfield", s.Count, s.Width(), PlayfieldWidth)
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, x := range s.X {
+		if x+s.Width() > PlayfieldWidth {
+			errs = append(errs, fmt.Errorf("base at x %d is %dpx wide and runs past the %dpx playfield", x, s.Width(), PlayfieldWidth))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// shieldLayoutWave returns the wave a layout file applies from.
+func shieldLayoutWave(filePath string) (int, error) {
+	name := strings.TrimSuffix(strings.TrimPrefix(path.Base(filePath), "wave"), ".txt")
+	wave, err := strconv.Atoi(name)
+	if err != nil || wave < 1 {
+		return 0, errors.New("name must be waveN.txt with N at least 1")
+	}
+	return wave, nil
+}
+
+func loadShieldLayout(filePath string) (int, *ShieldLayout, error) {
+	wave, err := shieldLayoutWave(filePath)
+	if err != nil {
+		return 0, nil, err
+	}
+	data, err := fs.ReadFile(source, filePath)
+	if err != nil {
+		return 0, nil, err
+	}
+	layout, err := ParseShieldLayout(bytes.NewReader(data))
+	return wave, layout, err
+}
+
+// shieldLayouts loads every layout file. Broken files are reported and skipped.
+func (l *loader) shieldLayouts() map[int]*ShieldLayout {
+	layouts := make(map[int]*ShieldLayout)
+
+	paths, err := fs.Glob(source, shieldLayoutPattern)
+	if err != nil {
+		l.errs = append(l.errs, err)
+	}
+	for _, filePath := range paths {
+		wave, layout, err := loadShieldLayout(filePath)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: %w", filePath, err))
+			continue
+		}
+		layouts[wave] = layout
+	}
+
+	if _, ok := layouts[1]; !ok {
+		data, err := fs.ReadFile(assets, firstShieldLayout)
+		if err == nil {
+			layouts[1], err = ParseShieldLayout(bytes.NewReader(data))
+		}
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("built-in %s: %w", firstShieldLayout, err))
+			delete(layouts, 1)
+		}
+	}
+	return layouts
+}
@@ -0,0 +1,74 @@
+package assets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseShieldLayoutFit(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  string
+		wantErr string
+	}{
+		{"four spread", "count 4\ncell 4\nshape\n########\n", ""},
+		{"placed", "x 0 288\ncell 4\nshape\n########\n", ""},
+		{"too many spread", "count 11\ncell 4\nshape\n########\n", "don't fit"},
+		{"placed past the edge", "x 0 290\ncell 4\nshape\n########\n", "base at x 290"},
+		{"placed past the screen", "x 400\nshape\n#\n", "base at x 400"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseShieldLayout(strings.NewReader(tt.layout))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParseShieldLayout: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseShieldLayout = %v, want an error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBrokenFirstShieldLayout(t *testing.T) {
+	t.Cleanup(func() {
+		source = assets
+		packID = ""
+		Load()
+	})
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(ManifestName, `{"name": "Broken", "version": "1.0"}`)
+	writeFile("shields/wave1.txt", "count 2\ncell 4\nshape\n####\n")
+	if _, err := UsePack(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Broken after the pack was checked, as an edit under hot reload can
+	writeFile("shields/wave1.txt", "count 2\nshape\n")
+	if err := Load(); err == nil || !strings.Contains(err.Error(), "shields/wave1.txt") {
+		t.Errorf("Load = %v, want the broken layout reported", err)
+	}
+	layout := ShieldLayoutFor(1)
+	if layout == nil {
+		t.Fatal("no layout for wave 1")
+	}
+	if layout.Count != 4 || layout.Cell != 4 {
+		t.Errorf("wave 1 has %d bases of %dpx cells, want the built-in 4 of 4px", layout.Count, layout.Cell)
+	}
+}
@@ -0,0 +1,178 @@
+package assets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"strings"
+	"time"
+)
+
+// Sheet is a sprite sheet cut into frames. Its image's bounds start at 0, 0.
+type Sheet struct {
+	Path   string
+	Image  *image.RGBA
+	Frames []Frame
+}
+
+// Frame is one cell of a sprite sheet.
+type Frame struct {
+	Rect     image.Rectangle // Where the frame is cut from the sheet
+	Duration time.Duration   // How long the frame shows, or 0 to change frame with each march step
+	Hitbox   image.Rectangle // Collision area, relative to the frame's top-left
+	Anchor   image.Point     // Point placed at the entity's position, relative to the frame's top-left
+	Mask     *Mask           // Solid pixels, from the frame's alpha
+}
+
+// FrameImage returns the pixels of frame i, for code that needs its colors.
+// The result's bounds are the frame's Rect, so use Bounds().Min.
+func (s *Sheet) FrameImage(i int) image.Image {
+	return s.Image.SubImage(s.Frames[i].Rect)
+}
+
+// sheetSource is how a sheet was loaded, so Reload can cut it again.
+type sheetSource struct {
+	sheet       *Sheet
+	frameWidth  int // Frame layout used without a sidecar
+	frameHeight int
+	frameCount  int
+}
+
+// sheetMeta is the sidecar JSON for a sprite sheet. It uses the layout of an
+// Aseprite "Array" JSON export, so those files work as-is; hitbox and anchor
+// are extensions that Aseprite doesn't write and can be added by hand.
+// Frames with a duration animate on their own; leave it out, or set it to 0,
+// for frames that should change as the aliens march, like the built-in ones.
+// invaders/topInvader.json is an example.
+//
+//	{"frames": [{"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "duration": 100,
+//	             "hitbox": {"x": 2, "y": 4, "w": 12, "h": 10}, "anchor": {"x": 0, "y": 0}}]}
+type sheetMeta struct {
+	Frames []frameMeta `json:"frames"`
+}
+
+type frameMeta struct {
+	Frame    metaRect   `json:"frame"`
+	Duration int        `json:"duration"` // Milliseconds
+	Hitbox   *metaRect  `json:"hitbox"`   // Defaults to the whole frame
+	Anchor   *metaPoint `json:"anchor"`   // Defaults to the top-left
+}
+
+type metaRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type metaPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func (r metaRect) rect() image.Rectangle {
+	return image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H)
+}
+
+// sidecarPath returns where the metadata for a sprite sheet lives.
+func sidecarPath(imagePath string) string {
+	return strings.TrimSuffix(imagePath, ".png") + ".json"
+}
+
+// sheet loads a sprite sheet and cuts it into frames. Without a sidecar the
+// sheet is a single row of frameCount frames of frameWidth x frameHeight.
+func (l *loader) sheet(filePath string, frameWidth, frameHeight, frameCount int) *Sheet {
+	img := l.decode(filePath, frameWidth*frameCount, frameHeight)
+	frames, err := cutFrames(filePath, img, frameWidth, frameHeight, frameCount)
+	if err != nil {
+		l.errs = append(l.errs, err)
+	}
+
+	sheet := &Sheet{Path: filePath, Image: img, Frames: frames}
+	sheets[filePath] = &sheetSource{sheet: sheet, frameWidth: frameWidth, frameHeight: frameHeight, frameCount: frameCount}
+	return sheet
+}
+
+// cutFrames cuts a sheet by its sidecar. Sheets without one, or whose sidecar
+// is broken, are cut into uniform frames; a broken sidecar is also reported.
+func cutFrames(filePath string, img *image.RGBA, frameWidth, frameHeight, frameCount int) ([]Frame, error) {
+	metaPath := sidecarPath(filePath)
+	data, err := fs.ReadFile(source, metaPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return uniformFrames(img, frameWidth, frameHeight, frameCount), nil
+	}
+
+	var meta sheetMeta
+	if err == nil {
+		err = json.Unmarshal(data, &meta)
+	}
+	if err == nil {
+		err = validateSheetMeta(meta, img.Bounds())
+	}
+	if err != nil {
+		return uniformFrames(img, frameWidth, frameHeight, frameCount), fmt.Errorf("%s: %w", metaPath, err)
+	}
+
+	frames := make([]Frame, len(meta.Frames))
+	for i, fm := range meta.Frames {
+		frameRect := fm.Frame.rect()
+		frames[i] = Frame{
+			Rect:     frameRect,
+			Duration: time.Duration(fm.Duration) * time.Millisecond,
+			Hitbox:   image.Rect(0, 0, frameRect.Dx(), frameRect.Dy()),
+			Mask:     newMask(img, frameRect),
+		}
+		if fm.Hitbox != nil {
+			frames[i].Hitbox = fm.Hitbox.rect()
+		}
+		if fm.Anchor != nil {
+			frames[i].Anchor = image.Pt(fm.Anchor.X, fm.Anchor.Y)
+		}
+	}
+	return frames, nil
+}
+
+func validateSheetMeta(meta sheetMeta, sheetBounds image.Rectangle) error {
+	if len(meta.Frames) == 0 {
+		return errors.New("no frames")
+	}
+
+	var errs []error
+	for i, fm := range meta.Frames {
+		frameRect := fm.Frame.rect()
+		if frameRect.Empty() || !frameRect.In(sheetBounds) {
+			errs = append(errs, fmt.Errorf("frame %d: %v is outside the %v sheet", i, frameRect, sheetBounds.Size()))
+		}
+		if fm.Duration < 0 {
+			errs = append(errs, fmt.Errorf("frame %d: negative duration", i))
+		}
+		frameBounds := image.Rect(0, 0, frameRect.Dx(), frameRect.Dy())
+		if fm.Hitbox != nil {
+			if hitbox := fm.Hitbox.rect(); hitbox.Empty() {
+				errs = append(errs, fmt.Errorf("frame %d: empty hitbox", i))
+			} else if !hitbox.In(frameBounds) {
+				errs = append(errs, fmt.Errorf("frame %d: hitbox %v is outside the %v frame", i, hitbox, frameBounds.Size()))
+			}
+		}
+		// An anchor may sit on the frame's right or bottom edge, e.g. at its feet
+		if fm.Anchor != nil && (fm.Anchor.X < 0 || fm.Anchor.Y < 0 || fm.Anchor.X > frameBounds.Dx() || fm.Anchor.Y > frameBounds.Dy()) {
+			errs = append(errs, fmt.Errorf("frame %d: anchor %d,%d is outside the %v frame", i, fm.Anchor.X, fm.Anchor.Y, frameBounds.Size()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func uniformFrames(img *image.RGBA, frameWidth, frameHeight, frameCount int) []Frame {
+	frames := make([]Frame, frameCount)
+	for i := range frames {
+		frameRect := image.Rect(i*frameWidth, 0, (i+1)*frameWidth, frameHeight)
+		frames[i] = Frame{
+			Rect:   frameRect,
+			Hitbox: image.Rect(0, 0, frameWidth, frameHeight),
+			Mask:   newMask(img, frameRect),
+		}
+	}
+	return frames
+}
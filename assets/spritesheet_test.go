@@ -0,0 +1,46 @@
+package assets
+
+import (
+	"encoding/json"
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestValidateSheetMeta(t *testing.T) {
+	sheetBounds := image.Rect(0, 0, 32, 16)
+
+	tests := []struct {
+		name    string
+		frame   string
+		wantErr string
+	}{
+		{"whole frame", `{"frame": {"x": 16, "y": 0, "w": 16, "h": 16}}`, ""},
+		{"hitbox and anchor inside", `{"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "hitbox": {"x": 2, "y": 4, "w": 12, "h": 10}, "anchor": {"x": 8, "y": 16}}`, ""},
+		{"frame outside the sheet", `{"frame": {"x": 24, "y": 0, "w": 16, "h": 16}}`, "outside the (32,16) sheet"},
+		{"negative duration", `{"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "duration": -1}`, "negative duration"},
+		{"empty hitbox", `{"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "hitbox": {"x": 0, "y": 0, "w": 0, "h": 16}}`, "empty hitbox"},
+		{"hitbox past the frame", `{"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "hitbox": {"x": 4, "y": 0, "w": 16, "h": 16}}`, "hitbox (4,0)-(20,16) is outside"},
+		{"hitbox above the frame", `{"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "hitbox": {"x": 0, "y": -2, "w": 16, "h": 10}}`, "is outside the (16,16) frame"},
+		{"anchor past the frame", `{"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "anchor": {"x": 17, "y": 0}}`, "anchor 17,0 is outside"},
+		{"negative anchor", `{"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "anchor": {"x": 0, "y": -1}}`, "anchor 0,-1 is outside"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var meta sheetMeta
+			if err := json.Unmarshal([]byte(`{"frames": [`+tt.frame+`]}`), &meta); err != nil {
+				t.Fatal(err)
+			}
+
+			err := validateSheetMeta(meta, sheetBounds)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("validateSheetMeta = %v, want no error", err)
+			case tt.wantErr != "" && err == nil:
+				t.Errorf("validateSheetMeta accepted the frame, want an error mentioning %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Errorf("validateSheetMeta = %q, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+import "invaders/game"
+
+// Sounds plays sound effects through the mixer. SoundBank is the real
+// implementation and RecordingAudio stands in for it when no audio device is
+// wanted.
+type Sounds interface {
+	game.Sounds
+	ApplyMixer()
+}
+
+// Music plays the background track. MusicPlayer is the real implementation.
+type Music interface {
+	PlayTrack(track MusicTrack)
+	SetIntensity(intensity float64)
+	Update()
+}
@@ -0,0 +1,116 @@
+package main
+
+import "invaders/game"
+
+type AudioEventKind int
+
+const (
+	EventPlay AudioEventKind = iota
+	EventPCM
+	EventLoopStart
+	EventLoopStop
+	EventMusic
+)
+
+// AudioEvent is one request made to a RecordingAudio.
+type AudioEvent struct {
+	Kind  AudioEventKind
+	Sound game.SoundID
+	X     float64    // Emitter position for EventPlay
+	Track MusicTrack // Track for EventMusic
+}
+
+// RecordingAudio implements Sounds and Music without an audio device. It
+// remembers every request so tests can check which sounds a game event made.
+type RecordingAudio struct {
+	Events    []AudioEvent
+	Intensity float64
+	loops     map[*recordedLoop]bool
+}
+
+type recordedLoop struct {
+	sound game.SoundID
+	x     float64 // Last pan position
+}
+
+var (
+	_ Sounds = (*RecordingAudio)(nil)
+	_ Music  = (*RecordingAudio)(nil)
+)
+
+func NewRecordingAudio() *RecordingAudio {
+	return &RecordingAudio{
+		loops: make(map[*recordedLoop]bool),
+	}
+}
+
+func (r *RecordingAudio) SampleRate() int {
+	return 44100
+}
+
+func (r *RecordingAudio) Play(id game.SoundID) {
+	r.PlayAt(id, game.Width/2)
+}
+
+func (r *RecordingAudio) PlayAt(id game.SoundID, x float64) {
+	r.Events = append(r.Events, AudioEvent{Kind: EventPlay, Sound: id, X: x})
+}
+
+func (r *RecordingAudio) PlayPCM(pcm []byte) {
+	r.Events = append(r.Events, AudioEvent{Kind: EventPCM})
+}
+
+func (r *RecordingAudio) Loop(id game.SoundID, gain float64) (game.SoundLoop, error) {
+	loop := &recordedLoop{sound: id}
+	r.loops[loop] = true
+	r.Events = append(r.Events, AudioEvent{Kind: EventLoopStart, Sound: id})
+	return loop, nil
+}
+
+func (r *RecordingAudio) StopLoop(loop game.SoundLoop) {
+	recorded := loop.(*recordedLoop)
+	delete(r.loops, recorded)
+	r.Events = append(r.Events, AudioEvent{Kind: EventLoopStop, Sound: recorded.sound})
+}
+
+func (l *recordedLoop) SetPan(x float64) {
+	l.x = x
+}
+
+func (r *RecordingAudio) ApplyMixer() {}
+
+func (r *RecordingAudio) Update() {}
+
+func (r *RecordingAudio) SetIntensity(intensity float64) {
+	r.Intensity = intensity
+}
+
+func (r *RecordingAudio) PlayTrack(track MusicTrack) {
+	r.Events = append(r.Events, AudioEvent{Kind: EventMusic, Track: track})
+}
+
+// Count returns how many events of a kind were recorded for a sound.
+func (r *RecordingAudio) Count(kind AudioEventKind, id game.SoundID) int {
+	count := 0
+	for _, event := range r.Events {
+		if event.Kind == kind && event.Sound == id {
+			count++
+		}
+	}
+	return count
+}
+
+// Looping reports whether a loop of the sound has started and not been stopped.
+func (r *RecordingAudio) Looping(id game.SoundID) bool {
+	for loop := range r.loops {
+		if loop.sound == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset forgets recorded events but keeps track of loops still playing.
+func (r *RecordingAudio) Reset() {
+	r.Events = nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import "invaders/game"
+
+// bonusLifePresets are the rules the options screen cycles through. The first
+// is the arcade's single extra life at 1500.
+var bonusLifePresets = []game.BonusLife{
+	{At: 1500},
+	{At: 1000},
+	{At: 5000, Every: 5000},
+	{At: 10000, Every: 10000},
+	{},
+}
+
+// cycleBonusLife returns the preset next to rule in the given direction. A
+// rule hand-edited into the settings file steps onto the first or last preset.
+func cycleBonusLife(rule game.BonusLife, direction int) game.BonusLife {
+	n := len(bonusLifePresets)
+	for i, preset := range bonusLifePresets {
+		if preset == rule {
+			return bonusLifePresets[(i+direction+n)%n]
+		}
+	}
+	if direction < 0 {
+		return bonusLifePresets[n-1]
+	}
+	return bonusLifePresets[0]
+}
@@ -0,0 +1,51 @@
+// Command leaderboard runs the reference leaderboard server, storing scores
+// in a local JSON file. The protocol is documented in package leaderboard.
+//
+// Every submission is checked by re-simulating its replay against the
+// game's rules, so the server must be built from the same version as the
+// game, and given the same -pack if players use one. It doesn't need a
+// graphics or audio device.
+//
+//	go run ./cmd/leaderboard -addr localhost:8080 -file scores.json
+//	go run . -leaderboard http://localhost:8080
+package main
+
+import (
+	"flag"
+	"invaders/assets"
+	"invaders/game"
+	"invaders/leaderboard"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	file := flag.String("file", "leaderboard.json", "JSON file to keep scores in")
+	pack := flag.String("pack", "", "content pack the game is played with, to check replays against")
+	flag.Parse()
+
+	if *pack != "" {
+		manifest, err := assets.UsePack(*pack)
+		if err != nil {
+			log.Fatalf("Error loading content pack:\n%v", err)
+		}
+		log.Printf("Checking replays against content pack %q %s", manifest.Name, manifest.Version)
+	}
+
+	// Replays are checked against the same rules and sprites the game loads
+	if err := assets.Load(); err != nil {
+		log.Fatalf("Error loading assets:\n%v", err)
+	}
+
+	server, err := leaderboard.NewServer(*file)
+	if err != nil {
+		log.Fatalf("Error loading scores: %v", err)
+	}
+	server.Verify = func(score leaderboard.Score) error {
+		return game.VerifyScore(score.Seed, score.Replay, score.Score, score.Wave)
+	}
+
+	log.Printf("Leaderboard listening on http://%s, storing scores in %s", *addr, *file)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
@@ -0,0 +1,66 @@
+// Command sfxexport renders the game's synthesized sound effects to WAV files
+// so they can be auditioned while tweaking a config.
+//
+//	go run ./cmd/sfxexport -config sfx.json -out sfx
+package main
+
+import (
+	"flag"
+	"fmt"
+	"invaders/sfxr"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+func main() {
+	configPath := flag.String("config", "", "JSON file of effect parameters (defaults to the built-in presets)")
+	outDir := flag.String("out", ".", "directory to write WAV files to")
+	sampleRate := flag.Int("rate", 44100, "sample rate in Hz, matching the game's audio context")
+	flag.Parse()
+
+	effects := sfxr.Presets()
+	if *configPath != "" {
+		file, err := os.Open(*configPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		effects, err = sfxr.LoadConfig(file)
+		file.Close()
+		if err != nil {
+			log.Fatalf("Error loading %s: %v", *configPath, err)
+		}
+	}
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	names := make([]string, 0, len(effects))
+	for name := range effects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(*outDir, name+".wav")
+		if err := exportWAV(path, effects[name], *sampleRate); err != nil {
+			log.Fatalf("Error writing %s: %v", path, err)
+		}
+		fmt.Println(path)
+	}
+}
+
+func exportWAV(path string, params sfxr.Params, sampleRate int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := sfxr.WriteWAV(file, sfxr.Render(params, sampleRate), sampleRate); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"invaders/textures"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const drawStatsReportFrames = 60 // Frames averaged per report
+
+// DrawStats counts the sprites GameScene draws and how many batches they
+// need. A batch ends whenever a draw uses a different texture from the one
+// before it, so comparing runs with and without -no-atlas shows what the
+// atlas saves.
+type DrawStats struct {
+	lastTexture  int
+	frameDraws   int
+	totalDraws   int
+	totalBatches int
+	frames       int
+}
+
+func (s *DrawStats) Record(img *ebiten.Image) {
+	texture := textures.TextureID(img)
+	// Images from outside the asset loader always count as their own batch
+	if s.frameDraws == 0 || texture < 0 || texture != s.lastTexture {
+		s.totalBatches++
+	}
+	s.lastTexture = texture
+	s.frameDraws++
+	s.totalDraws++
+}
+
+// EndFrame closes the current frame and logs averages once enough have passed.
+func (s *DrawStats) EndFrame() {
+	s.frameDraws = 0
+	s.frames++
+	if s.frames < drawStatsReportFrames {
+		return
+	}
+
+	log.Printf("draw stats: %.1f draws, %.1f batches per frame (atlas %v)",
+		float64(s.totalDraws)/float64(s.frames), float64(s.totalBatches)/float64(s.frames), textures.UseAtlas)
+	*s = DrawStats{}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"invaders/assets"
+	"invaders/game"
+	"invaders/textures"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// newDrawScene sets up a representative game frame, with shots, shields, a
+// UFO and the HUD on screen, drawn from textures loaded with or without the atlas.
+func newDrawScene(tb testing.TB, useAtlas bool) *GameScene {
+	tb.Helper()
+
+	textures.UseAtlas = useAtlas
+	tb.Cleanup(func() { textures.UseAtlas = true })
+	if err := textures.Load(); err != nil {
+		tb.Fatal(err)
+	}
+
+	g, _ := newRecordedGameScene()
+	g.sceneManager.highScores = &HighScoreTable{}
+	g.game.SpawnUFO()
+	g.game.Player.Missiles = append(g.game.Player.Missiles, game.NewPlayerMissile(g.game.Player))
+	for _, x := range []int{60, 160, 260} {
+		g.game.AlienMissiles = append(g.game.AlienMissiles, &game.AlienMissile{Sprite: assets.AlienShot, X: x, Y: 120})
+	}
+	return g
+}
+
+// drawFrame draws one frame of the scene and returns what it cost.
+func drawFrame(g *GameScene, screen *ebiten.Image) DrawStats {
+	g.sceneManager.drawStats = &DrawStats{}
+	g.Draw(screen)
+	return *g.sceneManager.drawStats
+}
+
+// renderFrame draws the representative frame once on a fresh screen.
+func renderFrame(tb testing.TB, useAtlas bool) DrawStats {
+	tb.Helper()
+
+	screen := ebiten.NewImage(game.Width, game.Height)
+	defer screen.Deallocate()
+	return drawFrame(newDrawScene(tb, useAtlas), screen)
+}
+
+func TestAtlasSavesBatches(t *testing.T) {
+	separate := renderFrame(t, false)
+	atlas := renderFrame(t, true)
+	t.Logf("separate textures: %d draws in %d batches", separate.totalDraws, separate.totalBatches)
+	t.Logf("atlas: %d draws in %d batches", atlas.totalDraws, atlas.totalBatches)
+
+	if atlas.totalDraws != separate.totalDraws {
+		t.Errorf("atlas drew %d sprites, separate textures %d", atlas.totalDraws, separate.totalDraws)
+	}
+	if atlas.totalBatches >= separate.totalBatches {
+		t.Errorf("atlas needed %d batches, no fewer than the %d without it", atlas.totalBatches, separate.totalBatches)
+	}
+}
+
+func TestShieldsDrawFromAtlas(t *testing.T) {
+	g := newDrawScene(t, true)
+	screen := ebiten.NewImage(game.Width, game.Height)
+	defer screen.Deallocate()
+
+	// Shoot a base from below, so what's drawn differs from a fresh one
+	base := g.game.Bases[0]
+	version := base.Version()
+	g.game.Player.Missiles = append(g.game.Player.Missiles, &game.PlayerMissile{
+		Sprite: assets.PlayerShot,
+		X:      base.X + base.Width/2,
+		Y:      base.Y + base.Height,
+	})
+	stepUntil(t, g, game.TicksPerSecond, func() bool { return base.Version() != version })
+
+	if stats := drawFrame(g, screen); stats.totalBatches != 1 {
+		t.Errorf("frame with shields needed %d batches, want 1", stats.totalBatches)
+	}
+
+	atlas := textures.TextureID(textures.Sprite(assets.Player))
+	for i, base := range g.game.Bases {
+		shield := g.shields[base]
+		if shield.slot < 0 || textures.TextureID(shield.image) != atlas {
+			t.Errorf("base %d is drawn from texture %d, not the atlas", i, textures.TextureID(shield.image))
+		}
+		if size := shield.image.Bounds().Size(); size != base.Bounds().Size() {
+			t.Errorf("base %d is drawn from a %v region, want %v", i, size, base.Bounds().Size())
+		}
+		if shield.version != base.Version() {
+			t.Errorf("base %d's damage wasn't written to the atlas", i)
+		}
+	}
+
+	// A later wave's layout takes over the slots of the bases it replaces
+	for wave, layout := range assets.ShieldLayouts {
+		g.game.Bases = game.CreateBases(layout, g.game.Player.Y)
+		if stats := drawFrame(g, screen); stats.totalBatches != 1 {
+			t.Errorf("frame with wave %d's shields needed %d batches, want 1", wave, stats.totalBatches)
+		}
+	}
+}
+
+func BenchmarkDrawFrame(b *testing.B) {
+	for _, bench := range []struct {
+		name     string
+		useAtlas bool
+	}{
+		{"separate", false},
+		{"atlas", true},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			g := newDrawScene(b, bench.useAtlas)
+			screen := ebiten.NewImage(game.Width, game.Height)
+			defer screen.Deallocate()
+
+			var stats DrawStats
+			b.ResetTimer()
+			for range b.N {
+				stats = drawFrame(g, screen)
+			}
+			b.ReportMetric(float64(stats.totalDraws), "draws/frame")
+			b.ReportMetric(float64(stats.totalBatches), "batches/frame")
+		})
+	}
+}
@@ -2,8 +2,13 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"image/color"
+	"invaders/game"
+	"invaders/leaderboard"
+	"log"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -11,11 +16,23 @@ import (
 	"golang.org/x/image/font/gofont/goregular"
 )
 
+// initialsAlphabet is cycled through with Up and Down when entering initials.
+const initialsAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 "
+
 type EndScene struct {
 	sceneManager *SceneManager
 	titleFont    *text.GoTextFace
 	subtitleFont *text.GoTextFace
 	finalScore   int
+	wave         int
+	seed         int64
+	replay       *game.Replay
+
+	enteringInitials bool
+	initials         [initialsLength]int // Index into initialsAlphabet per letter
+	cursor           int                 // Letter being entered
+	rank             int                 // Position of this game's score in the table, or -1
+	showOnline       bool                // Show the online leaderboard instead of the local table
 }
 
 func (t *EndScene) Draw(screen *ebiten.Image) {
@@ -29,7 +46,7 @@ func (t *EndScene) Draw(screen *ebiten.Image) {
 	titleText := "Game Over"
 	titleBounds, _ := text.Measure(titleText, t.titleFont, 0)
 	titleX := (w - int(titleBounds)) / 2
-	titleY := h/2 - 50
+	titleY := h/2 - 220
 
 	op := &text.DrawOptions{}
 	op.GeoM.Translate(float64(titleX), float64(titleY))
@@ -40,18 +57,32 @@ func (t *EndScene) Draw(screen *ebiten.Image) {
 	scoreText := fmt.Sprintf("Final Score: %d", t.finalScore)
 	scoreBounds, _ := text.Measure(scoreText, t.subtitleFont, 0)
 	scoreX := (w - int(scoreBounds)) / 2
-	scoreY := titleY + 50
+	scoreY := titleY + 60
 
 	op3 := &text.DrawOptions{}
 	op3.GeoM.Translate(float64(scoreX), float64(scoreY))
 	op3.ColorScale.ScaleWithColor(color.RGBA{255, 200, 100, 255}) // Golden color for score
 	text.Draw(screen, scoreText, t.subtitleFont, op3)
 
+	if t.enteringInitials {
+		t.drawInitialsEntry(screen, scoreY+60)
+		return
+	}
+
+	if t.showOnline {
+		t.drawOnlineTable(screen, scoreY+50)
+	} else {
+		t.drawTable(screen, scoreY+50)
+	}
+
 	// Draw restart instruction
 	subtitleText := "Press any key to restart"
+	if t.sceneManager.leaderboard != nil {
+		subtitleText = "Tab: local/online  Any other key to restart"
+	}
 	subtitleBounds, _ := text.Measure(subtitleText, t.subtitleFont, 0)
 	subtitleX := (w - int(subtitleBounds)) / 2
-	subtitleY := titleY + 100
+	subtitleY := h - 50
 
 	op2 := &text.DrawOptions{}
 	op2.GeoM.Translate(float64(subtitleX), float64(subtitleY))
@@ -59,34 +90,202 @@ func (t *EndScene) Draw(screen *ebiten.Image) {
 	text.Draw(screen, subtitleText, t.subtitleFont, op2)
 }
 
+// drawInitialsEntry draws the arcade-style "A A A" entry with the current letter highlighted.
+func (t *EndScene) drawInitialsEntry(screen *ebiten.Image, y int) {
+	w := screen.Bounds().Dx()
+
+	promptText := "Enter your initials"
+	if t.sceneManager.highScores.Qualifies(t.finalScore) {
+		promptText = "New high score! Enter your initials"
+	}
+	promptBounds, _ := text.Measure(promptText, t.subtitleFont, 0)
+	promptOp := &text.DrawOptions{}
+	promptOp.GeoM.Translate(float64((w-int(promptBounds))/2), float64(y))
+	promptOp.ColorScale.ScaleWithColor(color.RGBA{255, 200, 100, 255})
+	text.Draw(screen, promptText, t.subtitleFont, promptOp)
+
+	letterWidth := 48
+	startX := (w - letterWidth*initialsLength) / 2
+	for i, index := range t.initials {
+		letterColor := color.RGBA{200, 150, 150, 255}
+		if i == t.cursor {
+			letterColor = color.RGBA{255, 255, 255, 255}
+		}
+
+		letterOp := &text.DrawOptions{}
+		letterOp.GeoM.Translate(float64(startX+i*letterWidth), float64(y+50))
+		letterOp.ColorScale.ScaleWithColor(letterColor)
+		text.Draw(screen, string(initialsAlphabet[index]), t.titleFont, letterOp)
+	}
+
+	helpText := "Up/Down letter  Left/Right move  Enter done"
+	helpBounds, _ := text.Measure(helpText, t.subtitleFont, 0)
+	helpOp := &text.DrawOptions{}
+	helpOp.GeoM.Translate(float64((w-int(helpBounds))/2), float64(y+130))
+	helpOp.ColorScale.ScaleWithColor(color.RGBA{200, 150, 150, 255})
+	text.Draw(screen, helpText, t.subtitleFont, helpOp)
+}
+
+// drawTable lists the high scores, highlighting the one from this game.
+func (t *EndScene) drawTable(screen *ebiten.Image, y int) {
+	w := screen.Bounds().Dx()
+
+	for i, entry := range t.sceneManager.highScores.Entries {
+		rowText := fmt.Sprintf("%2d. %-3s %7d  W%-2d %s", i+1, entry.Initials, entry.Score, entry.Wave, entry.Date.Format("2006-01-02"))
+		rowBounds, _ := text.Measure(rowText, t.subtitleFont, 0)
+
+		rowColor := color.RGBA{200, 150, 150, 255}
+		if i == t.rank {
+			rowColor = color.RGBA{255, 255, 255, 255}
+		}
+
+		rowOp := &text.DrawOptions{}
+		rowOp.GeoM.Translate(float64((w-int(rowBounds))/2), float64(y+i*28))
+		rowOp.ColorScale.ScaleWithColor(rowColor)
+		text.Draw(screen, rowText, t.subtitleFont, rowOp)
+	}
+}
+
+// drawOnlineTable lists the top scores from the leaderboard server.
+func (t *EndScene) drawOnlineTable(screen *ebiten.Image, y int) {
+	w := screen.Bounds().Dx()
+	client := t.sceneManager.leaderboard
+
+	rows := []string{"ONLINE"}
+	top := client.Top()
+	switch {
+	case top == nil && client.Err() != nil:
+		rows = append(rows, "Leaderboard unavailable, retrying")
+	case top == nil:
+		rows = append(rows, "Loading...")
+	case len(top) == 0:
+		rows = append(rows, "No scores yet")
+	}
+	for i, entry := range top {
+		rows = append(rows, fmt.Sprintf("%2d. %-3s %7d  W%-2d %s", i+1, entry.Initials, entry.Score, entry.Wave, entry.Date.Format("2006-01-02")))
+	}
+	if pending := client.Pending(); pending > 0 {
+		rows = append(rows, fmt.Sprintf("%d score(s) waiting to send", pending))
+	}
+
+	for i, rowText := range rows {
+		rowBounds, _ := text.Measure(rowText, t.subtitleFont, 0)
+		rowOp := &text.DrawOptions{}
+		rowOp.GeoM.Translate(float64((w-int(rowBounds))/2), float64(y+i*26))
+		rowOp.ColorScale.ScaleWithColor(color.RGBA{200, 150, 150, 255})
+		text.Draw(screen, rowText, t.subtitleFont, rowOp)
+	}
+}
+
 func (t *EndScene) Update() error {
+	if t.enteringInitials {
+		t.updateInitials()
+		return nil
+	}
+
+	if t.sceneManager.leaderboard != nil && inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		t.showOnline = !t.showOnline
+		if t.showOnline {
+			t.sceneManager.leaderboard.Refresh()
+		}
+		return nil
+	}
+
 	// Check for key presses
-	if ebiten.IsKeyPressed(ebiten.KeySpace) ||
-		ebiten.IsKeyPressed(ebiten.KeyEnter) ||
-		ebiten.IsKeyPressed(ebiten.KeyEscape) ||
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyEscape) ||
 		inpututil.IsKeyJustPressed(ebiten.KeyA) ||
 		inpututil.IsKeyJustPressed(ebiten.KeyS) ||
 		inpututil.IsKeyJustPressed(ebiten.KeyD) ||
 		inpututil.IsKeyJustPressed(ebiten.KeyW) {
-		t.sceneManager.gameScene = NewGameScene(t.sceneManager)
-		t.sceneManager.TransitionTo(SceneGame)
+		t.sceneManager.StartGame()
 		return nil
 	}
 	// Check for mouse clicks
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
 		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
-		t.sceneManager.gameScene = NewGameScene(t.sceneManager)
-		t.sceneManager.TransitionTo(SceneGame)
+		t.sceneManager.StartGame()
 		return nil
 	}
 	return nil
 }
 
+func (t *EndScene) updateInitials() {
+	letter := &t.initials[t.cursor]
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || inpututil.IsKeyJustPressed(ebiten.KeyW) {
+		*letter = (*letter + 1) % len(initialsAlphabet)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		*letter = (*letter + len(initialsAlphabet) - 1) % len(initialsAlphabet)
+	}
+	if (inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || inpututil.IsKeyJustPressed(ebiten.KeyA) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyBackspace)) && t.cursor > 0 {
+		t.cursor--
+	}
+
+	advance := inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || inpututil.IsKeyJustPressed(ebiten.KeyD) ||
+		inpututil.IsKeyJustPressed(ebiten.KeySpace)
+	if advance && t.cursor < initialsLength-1 {
+		t.cursor++
+	} else if advance || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		t.saveScore()
+	}
+}
+
+// saveScore puts this game on the high score table and persists it.
+func (t *EndScene) saveScore() {
+	initials := make([]byte, initialsLength)
+	for i, index := range t.initials {
+		initials[i] = initialsAlphabet[index]
+	}
+
+	entry := HighScore{
+		Score:      t.finalScore,
+		Initials:   string(initials),
+		Wave:       t.wave,
+		Difficulty: defaultDifficulty,
+		Date:       time.Now(),
+	}
+	t.enteringInitials = false
+
+	table := t.sceneManager.highScores
+	t.rank = table.Add(entry)
+	if t.rank >= 0 {
+		if err := table.Save(); err != nil {
+			log.Printf("Error saving high scores: %v", err)
+		}
+	}
+
+	// Sent in the background; failures are retried without holding up the game
+	if client := t.sceneManager.leaderboard; client != nil {
+		if t.replay != nil && t.replay.Dev {
+			log.Printf("Not submitting to the leaderboard: games played with -dev can't be verified")
+			return
+		}
+		replay, err := json.Marshal(t.replay)
+		if err != nil {
+			log.Printf("Error encoding replay: %v", err)
+			return
+		}
+		client.Submit(leaderboard.Score{
+			Initials:   entry.Initials,
+			Score:      entry.Score,
+			Wave:       entry.Wave,
+			Difficulty: entry.Difficulty,
+			Date:       entry.Date,
+			Seed:       t.seed,
+			Replay:     replay,
+		})
+		client.Refresh()
+	}
+}
+
 func (t *EndScene) Layout(outerWidth, outerHeight int) (int, int) {
 	return outerWidth, outerHeight
 }
 
-func NewEndScene(sm *SceneManager, finalScore int) *EndScene {
+func NewEndScene(sm *SceneManager, finalScore, wave int) *EndScene {
 	// Create fonts (same pattern as TitleScene)
 	titleFontSource, _ := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
 	titleFont := &text.GoTextFace{
@@ -101,9 +300,12 @@ func NewEndScene(sm *SceneManager, finalScore int) *EndScene {
 	}
 
 	return &EndScene{
-		sceneManager: sm,
-		titleFont:    titleFont,
-		subtitleFont: subtitleFont,
-		finalScore:   finalScore,
+		sceneManager:     sm,
+		titleFont:        titleFont,
+		subtitleFont:     subtitleFont,
+		finalScore:       finalScore,
+		wave:             wave,
+		enteringInitials: sm.highScores.Qualifies(finalScore) || (sm.leaderboard != nil && finalScore > 0),
+		rank:             -1,
 	}
 }
@@ -1,9 +1,8 @@
-package main
+package game
 
 import (
+	"image"
 	"invaders/assets"
-
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type AlienType int
@@ -17,20 +16,22 @@ const (
 	NUMBER_OF_ALIENS_IN_ROW = 12
 	ALIEN_SIZE              = 16
 	PADDING                 = 64
+	ALIENS_PER_WAVE         = NUMBER_OF_ALIENS_IN_ROW * 5
 )
 
 type Alien struct {
-	Sprite       []*ebiten.Image
+	Sheet        *assets.Sheet
 	X            int
 	Y            int
 	PointsValue  int
 	AlienType    AlienType
 	CurrentFrame int
+	FrameTicks   int // Ticks the current frame has shown, for frames with a duration
 }
 
 func NewAlien(a AlienType) *Alien {
 	return &Alien{
-		Sprite:       GetAlienSpriteByType(a),
+		Sheet:        GetAlienSpriteByType(a),
 		PointsValue:  getAlienPointsByType(a),
 		AlienType:    AlienType(a),
 		CurrentFrame: 0,
@@ -51,7 +52,7 @@ func getAlienPointsByType(a AlienType) int {
 	}
 }
 
-func GetAlienSpriteByType(a AlienType) []*ebiten.Image {
+func GetAlienSpriteByType(a AlienType) *assets.Sheet {
 	switch a {
 	case SquidAlien:
 		return assets.TopInvaderAnimation
@@ -63,10 +64,50 @@ func GetAlienSpriteByType(a AlienType) []*ebiten.Image {
 	}
 }
 
-
-// ToggleFrame switches between animation frames (0 and 1)
+// ToggleFrame advances to the next animation frame on a march step. Frames
+// with a duration are left to Animate.
 func (a *Alien) ToggleFrame() {
-	a.CurrentFrame = (a.CurrentFrame + 1) % 2
+	if a.Frame().Duration == 0 {
+		a.nextFrame()
+	}
+}
+
+// Animate counts one tick of a frame with a duration, moving on once it has
+// shown for that long.
+func (a *Alien) Animate() {
+	duration := a.Frame().Duration
+	if duration == 0 {
+		return
+	}
+	if a.FrameTicks++; a.FrameTicks >= max(1, int(duration.Milliseconds())*TicksPerSecond/1000) {
+		a.nextFrame()
+	}
+}
+
+// nextFrame shows the next frame of the sheet, wrapping at the end.
+func (a *Alien) nextFrame() {
+	a.CurrentFrame = (a.CurrentFrame + 1) % len(a.Sheet.Frames)
+	a.FrameTicks = 0
+}
+
+// Frame returns the current animation frame
+func (a *Alien) Frame() assets.Frame {
+	return a.Sheet.Frames[a.CurrentFrame]
+}
+
+// DrawPosition returns where the current frame's top-left goes, honouring its anchor
+func (a *Alien) DrawPosition() image.Point {
+	return image.Pt(a.X, a.Y).Sub(a.Frame().Anchor)
+}
+
+// Hitbox returns the current frame's collision area in playfield coordinates
+func (a *Alien) Hitbox() image.Rectangle {
+	return a.Frame().Hitbox.Add(a.DrawPosition())
+}
+
+// Mask returns the current frame's solid pixels, placed at DrawPosition
+func (a *Alien) Mask() *assets.Mask {
+	return a.Frame().Mask
 }
 
 func SpawnAlienWave() []*Alien {
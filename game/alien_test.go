@@ -0,0 +1,49 @@
+package game
+
+import (
+	"image"
+	"invaders/assets"
+	"testing"
+	"time"
+)
+
+func TestAlienAnimation(t *testing.T) {
+	frame := func(duration time.Duration) assets.Frame {
+		return assets.Frame{Rect: image.Rect(0, 0, 16, 16), Hitbox: image.Rect(0, 0, 16, 16), Duration: duration}
+	}
+
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		ticks     int  // Ticks to animate for
+		march     bool // Take a march step afterwards
+		wantFrame int
+	}{
+		{"march steps change frames without durations", []time.Duration{0, 0}, 100, true, 1},
+		{"ticks alone don't change frames without durations", []time.Duration{0, 0}, 100, false, 0},
+		{"a frame shows for its duration", []time.Duration{100 * time.Millisecond, 100 * time.Millisecond}, 5, false, 0},
+		{"the next frame follows after its duration", []time.Duration{100 * time.Millisecond, 100 * time.Millisecond}, 6, false, 1},
+		{"each frame keeps its own duration", []time.Duration{50 * time.Millisecond, 500 * time.Millisecond}, 30, false, 1},
+		{"animation wraps at the end of the sheet", []time.Duration{50 * time.Millisecond, 50 * time.Millisecond}, 6, false, 0},
+		{"march steps leave timed frames alone", []time.Duration{100 * time.Millisecond, 100 * time.Millisecond}, 1, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet := &assets.Sheet{}
+			for _, duration := range tt.durations {
+				sheet.Frames = append(sheet.Frames, frame(duration))
+			}
+			alien := &Alien{Sheet: sheet}
+
+			for range tt.ticks {
+				alien.Animate()
+			}
+			if tt.march {
+				alien.ToggleFrame()
+			}
+			if alien.CurrentFrame != tt.wantFrame {
+				t.Errorf("showing frame %d, want %d", alien.CurrentFrame, tt.wantFrame)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"invaders/assets"
+)
+
+// baseColor is the shield color, the brightest entry of the sprite palette.
+var baseColor = color.RGBA{208, 208, 88, 255}
+
+// Craters blown out of a base, centered on the impact point. Player shots
+// explode upward from below; alien shots splatter downward from above.
+var (
+	playerShotCrater = parseCrater(
+		"#...#..#",
+		"..#...#.",
+		".######.",
+		"########",
+		"########",
+		".######.",
+		"..#..#..",
+		"#..#...#",
+	)
+	alienShotCrater = parseCrater(
+		"..#...",
+		"#...#.",
+		"..##.#",
+		".####.",
+		"#.###.",
+		".####.",
+		"#.#.#.",
+		"..#..#",
+	)
+)
+
+// Crater is the set of pixels an explosion removes from a base.
+type Crater struct {
+	Width  int
+	Height int
+	cells  []bool
+}
+
+func parseCrater(rows ...string) *Crater {
+	c := &Crater{Width: len(rows[0]), Height: len(rows)}
+	c.cells = make([]bool, c.Width*c.Height)
+	for y, row := range rows {
+		for x, ch := range row {
+			c.cells[y*c.Width+x] = ch == '#'
+		}
+	}
+	return c
+}
+
+// shieldPixel is one pixel of a base. It's destroyed when hp reaches zero.
+type shieldPixel struct {
+	material *Material
+	hp       int
+}
+
+// Base is a shield whose pixels are worn down one explosion at a time, so
+// shots tunnel through it gradually.
+type Base struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+
+	layout  *assets.ShieldLayout // Shape the base was built from, for restoring it
+	pixels  []shieldPixel
+	version int // Bumped whenever a pixel changes
+	ticks   int
+}
+
+func NewBase(layout *assets.ShieldLayout, baseX, baseY int) *Base {
+	width, height := layout.Width(), layout.Height()
+	base := &Base{
+		X:      baseX,
+		Y:      baseY,
+		Width:  width,
+		Height: height,
+		layout: layout,
+		pixels: make([]shieldPixel, width*height),
+	}
+	base.Restore(1)
+
+	return base
+}
+
+// Bounds returns the base's area on the playfield.
+func (b *Base) Bounds() image.Rectangle {
+	return image.Rect(b.X, b.Y, b.X+b.Width, b.Y+b.Height)
+}
+
+// pixel returns the shield pixel at playfield x, y, or nil outside the base.
+func (b *Base) pixel(x, y int) *shieldPixel {
+	x -= b.X
+	y -= b.Y
+	if x < 0 || y < 0 || x >= b.Width || y >= b.Height {
+		return nil
+	}
+	return &b.pixels[y*b.Width+x]
+}
+
+// Solid reports whether the playfield pixel at x, y is part of the shield.
+func (b *Base) Solid(x, y int) bool {
+	p := b.pixel(x, y)
+	return p != nil && p.hp > 0
+}
+
+// Impact finds where a sprite at pos first touches the shield. Shots moving
+// up hit the lowest solid pixel they overlap, shots moving down the highest.
+func (b *Base) Impact(mask *assets.Mask, pos image.Point, movingUp bool) (image.Point, bool) {
+	overlap := mask.Bounds(pos).Intersect(b.Bounds())
+	found := false
+	var impact image.Point
+
+	for y := overlap.Min.Y; y < overlap.Max.Y; y++ {
+		for x := overlap.Min.X; x < overlap.Max.X; x++ {
+			if !mask.Solid(x-pos.X, y-pos.Y) || !b.Solid(x, y) {
+				continue
+			}
+			if !found || (movingUp && y > impact.Y) {
+				impact = image.Pt(x, y)
+				found = true
+			}
+		}
+	}
+	return impact, found
+}
+
+// Blast takes a hit point from every pixel of a crater centered on the
+// playfield point at.
+func (b *Base) Blast(crater *Crater, at image.Point) {
+	origin := at.Sub(image.Pt(crater.Width/2, crater.Height/2))
+	for y := 0; y < crater.Height; y++ {
+		for x := 0; x < crater.Width; x++ {
+			if crater.cells[y*crater.Width+x] {
+				b.damage(origin.X+x, origin.Y+y, 1)
+			}
+		}
+	}
+}
+
+// Touch applies each material's reaction to an alien whose solid pixels are
+// at pos. It reports whether the alien touched an energy shield and was zapped.
+func (b *Base) Touch(mask *assets.Mask, pos image.Point) bool {
+	zapped := false
+	overlap := mask.Bounds(pos).Intersect(b.Bounds())
+	for y := overlap.Min.Y; y < overlap.Max.Y; y++ {
+		for x := overlap.Min.X; x < overlap.Max.X; x++ {
+			if !mask.Solid(x-pos.X, y-pos.Y) || !b.Solid(x, y) {
+				continue
+			}
+
+			switch p := b.pixel(x, y); p.material.Contact {
+			case ContactCrumble:
+				b.damage(x, y, p.hp)
+			case ContactZap:
+				b.damage(x, y, p.hp)
+				zapped = true
+			}
+		}
+	}
+	return zapped
+}
+
+func (b *Base) damage(x, y, hits int) {
+	p := b.pixel(x, y)
+	if p == nil || p.hp == 0 {
+		return
+	}
+	p.hp = max(0, p.hp-hits)
+	b.version++
+}
+
+// Update heals damaged pixels of materials that recharge.
+func (b *Base) Update() {
+	b.ticks++
+	if b.ticks%energyRechargeTicks != 0 {
+		return
+	}
+
+	for i := range b.pixels {
+		p := &b.pixels[i]
+		if p.material != nil && p.material.Recharge && p.hp > 0 && p.hp < p.material.HP {
+			p.hp++
+			b.version++
+		}
+	}
+}
+
+// Restore rebuilds a share of the base's damage, from 0 for none to 1 for
+// good as new. Pixels are picked in a dither pattern so repairs spread evenly.
+func (b *Base) Restore(share float64) {
+	for y := 0; y < b.Height; y++ {
+		for x := 0; x < b.Width; x++ {
+			cell := b.layout.At(x, y)
+			if cell == 0 || restoreDither(x, y) >= share {
+				continue
+			}
+			material := materialForCell(cell)
+			b.pixels[y*b.Width+x] = shieldPixel{material: material, hp: material.HP}
+		}
+	}
+	b.version++
+}
+
+// Version changes whenever the base's pixels or the sheets coloring them do,
+// so a drawn copy of the base knows when it needs refreshing.
+func (b *Base) Version() int {
+	return b.version + materialsVersion
+}
+
+// Pixels renders the base as RGBA bytes, row by row, for drawing.
+func (b *Base) Pixels() []byte {
+	pixels := make([]byte, b.Width*b.Height*4)
+	for i, p := range b.pixels {
+		if p.hp == 0 {
+			continue
+		}
+		r, g, bl, a := p.material.Color(i%b.Width, i/b.Width, p.hp).RGBA()
+		pixels[i*4] = byte(r >> 8)
+		pixels[i*4+1] = byte(g >> 8)
+		pixels[i*4+2] = byte(bl >> 8)
+		pixels[i*4+3] = byte(a >> 8)
+	}
+	return pixels
+}
+
+// CreateBases lays out the bases for a wave above the player.
+func CreateBases(layout *assets.ShieldLayout, playerY int) []*Base {
+	if layout == nil {
+		return nil // Only if even the built-in layout failed to load
+	}
+
+	baseY := playerY - layout.Gap - layout.Height() // Gap above player, minus base height
+
+	positions := layout.X
+	if len(positions) == 0 {
+		// Equal spacing between and around bases
+		spacing := (Width - (layout.Count * layout.Width())) / (layout.Count + 1)
+		for i := 0; i < layout.Count; i++ {
+			positions = append(positions, spacing+(i*(layout.Width()+spacing)))
+		}
+	}
+
+	bases := make([]*Base, len(positions))
+	for i, baseX := range positions {
+		bases[i] = NewBase(layout, baseX, baseY)
+	}
+
+	return bases
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"fmt"
+	"math"
+)
+
+// BonusLife says at which scores the player earns an extra life.
+type BonusLife struct {
+	At    int `json:"at"`    // First milestone, or 0 for no bonus lives
+	Every int `json:"every"` // Points between later milestones, or 0 to award only one
+}
+
+// Next returns the milestone after the given one, or 0 once no more lives are awarded.
+func (b BonusLife) Next(milestone int) int {
+	if b.Every <= 0 {
+		return 0
+	}
+	return milestone + b.Every
+}
+
+func (b BonusLife) String() string {
+	switch {
+	case b.At <= 0:
+		return "Off"
+	case b.Every <= 0:
+		return fmt.Sprintf("At %d", b.At)
+	case b.Every == b.At:
+		return fmt.Sprintf("Every %d", b.Every)
+	default:
+		return fmt.Sprintf("%d+%d", b.At, b.Every)
+	}
+}
+
+// bonusLifeNotes is the rising arpeggio played when a life is earned, in Hz.
+var bonusLifeNotes = [4]float64{523.25, 659.25, 783.99, 1046.50}
+
+const (
+	bonusLifeNoteLength = 0.07 // Seconds per note of the jingle
+	bonusLifeFlashTicks = 120  // How long the lives counter flashes
+)
+
+// synthBonusLifeJingle renders the extra life jingle as 16-bit stereo PCM,
+// a bright square arpeggio well above the march so the two never blur.
+func synthBonusLifeJingle(sampleRate int) []byte {
+	noteSamples := int(bonusLifeNoteLength * float64(sampleRate))
+	samples := noteSamples * len(bonusLifeNotes)
+
+	pcm := make([]byte, samples*4)
+	for i := 0; i < samples; i++ {
+		freq := bonusLifeNotes[i/noteSamples]
+		t := float64(i) / float64(sampleRate)
+
+		value := 1.0
+		if math.Sin(2*math.Pi*freq*t) < 0 {
+			value = -1.0
+		}
+		// Each note decays a little; the last one rings out
+		envelope := math.Exp(-3 * float64(i%noteSamples) / float64(noteSamples))
+		if i >= samples-noteSamples {
+			envelope = math.Exp(-2 * float64(i%noteSamples) / float64(noteSamples))
+		}
+		sample := int16(value * envelope * 0.2 * math.MaxInt16)
+
+		PutStereoSample(pcm, i, sample)
+	}
+	return pcm
+}
@@ -0,0 +1,25 @@
+package game
+
+import (
+	"image"
+	"invaders/assets"
+)
+
+// spritesOverlap reports whether the solid pixels of two sprites touch.
+func spritesOverlap(a *assets.Sprite, aPos image.Point, b *assets.Sprite, bPos image.Point) bool {
+	return a.Mask.Overlaps(aPos, b.Mask, bPos)
+}
+
+// alienHit reports whether a sprite at pos touches an alien. The sprite has
+// to reach the alien's hitbox and one of its solid pixels, so shots through
+// the gaps between an invader's legs miss.
+func alienHit(sprite *assets.Sprite, pos image.Point, alien *Alien) bool {
+	if !sprite.Mask.Bounds(pos).Overlaps(alien.Hitbox()) {
+		return false
+	}
+	return sprite.Mask.Overlaps(pos, alien.Mask(), alien.DrawPosition())
+}
+
+func rectCenterX(r image.Rectangle) float64 {
+	return float64(r.Min.X+r.Max.X) / 2
+}
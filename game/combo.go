@@ -0,0 +1,49 @@
+package game
+
+const (
+	comboHitsPerLevel  = 4  // Chain needed to raise the multiplier by one
+	comboMaxMultiplier = 4  // Highest the multiplier goes
+	comboWindowTicks   = 45 // A kill this soon after the last counts twice towards the chain
+)
+
+// Combo tracks the player's chain of hits. Hits without a miss build the
+// chain, quick kills build it faster, and every few links raise the score
+// multiplier. A miss drops the multiplier a level; being hit loses it all.
+type Combo struct {
+	Chain  int // Hits counted towards the multiplier
+	Window int // Ticks left in which another hit counts double
+}
+
+// Multiplier returns the factor applied to points scored right now.
+func (c *Combo) Multiplier() int {
+	return min(1+c.Chain/comboHitsPerLevel, comboMaxMultiplier)
+}
+
+// Hit adds a hit to the chain.
+func (c *Combo) Hit() {
+	c.Chain++
+	if c.Window > 0 {
+		c.Chain++
+	}
+	// Cap the chain at the top level, so a single miss always costs a level
+	c.Chain = min(c.Chain, (comboMaxMultiplier-1)*comboHitsPerLevel)
+	c.Window = comboWindowTicks
+}
+
+// Miss drops the multiplier to the start of the level below.
+func (c *Combo) Miss() {
+	c.Chain = max(0, (c.Multiplier()-2)*comboHitsPerLevel)
+	c.Window = 0
+}
+
+// Reset breaks the chain entirely, as when the player is hit.
+func (c *Combo) Reset() {
+	*c = Combo{}
+}
+
+// Update counts down the quick kill window by one tick.
+func (c *Combo) Update() {
+	if c.Window > 0 {
+		c.Window--
+	}
+}
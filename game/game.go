@@ -0,0 +1,541 @@
+// Package game holds the rules of Invaders. A Game advances one tick at a
+// time from player input and a seeded random source, and never draws or
+// reads the keyboard, so the same rules run in the window and on a
+// leaderboard server checking a replay.
+package game
+
+import (
+	"image"
+	"invaders/assets"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Size of the playfield in pixels
+const (
+	Width  = assets.PlayfieldWidth // Shield layouts are checked against it
+	Height = 240
+)
+
+// Options are the rules a player can choose. They change how a game plays
+// out, so replays record them.
+type Options struct {
+	MarchMode     MarchMode     `json:"marchMode"`
+	ShieldRestore ShieldRestore `json:"shieldRestore"`
+	BonusLife     BonusLife     `json:"bonusLife"`
+}
+
+// Game is one game in progress: the fleet, the player and the shields.
+type Game struct {
+	Aliens        []*Alien
+	Player        *Player
+	AlienMissiles []*AlienMissile
+	Bases         []*Base
+	UFO           *UFO
+	UFOScore      *UFOScorePopup // Score of the last UFO destroyed, while it shows
+	Lives         int
+	LivesFlash    int   // Ticks left flashing the lives counter after an extra life
+	Combo         Combo // Chain of hits that multiplies the score
+	Wave          int   // Current wave, starting at 1
+	Seed          int64 // Seeds rng, so the game can be replayed
+	Over          bool  // The game has ended
+
+	options          Options
+	sounds           Sounds
+	rng              *rand.Rand // Source of every random choice that affects play
+	timer            *Timer
+	currentDirection Direction
+	waveTimer        *Timer
+	deathTimer       *Timer
+	playerDead       bool
+	ufoTimer         *Timer
+	ufoSound         SoundLoop
+	nextBonusLife    int      // Score that earns the next extra life, or 0 if none are left
+	marchQueue       []*Alien // Aliens still to step in the current ripple pass
+	marchReverse     bool     // An alien reached the edge during this ripple pass
+	marchDown        bool     // The current ripple pass steps aliens down instead of across
+	marchNote        int      // Next note of the four-note march loop
+	waveShots        int      // ShotsFired when the current wave started
+	waveHits         int      // Player shots that hit an alien or the UFO this wave
+}
+
+type Direction int
+
+const (
+	LEFT Direction = iota
+	RIGHT
+)
+
+// New starts a game whose random choices all follow from seed. Its sound
+// effects play through sounds.
+func New(seed int64, options Options, sounds Sounds) *Game {
+	g := &Game{
+		Aliens:           SpawnAlienWave(),
+		timer:            NewTimer(1 * time.Second),
+		currentDirection: LEFT,
+		sounds:           sounds,
+		Player:           NewPlayer(),
+		waveTimer:        NewTimer(3 * time.Second),
+		AlienMissiles:    make([]*AlienMissile, 0),
+		deathTimer:       NewTimer(1500 * time.Millisecond), // 1.5 seconds
+		Lives:            5,
+		nextBonusLife:    options.BonusLife.At,
+		options:          options,
+		Wave:             1,
+		Seed:             seed,
+		rng:              rand.New(rand.NewSource(seed)),
+	}
+
+	// Create bases positioned above the player
+	g.Bases = CreateBases(assets.ShieldLayoutFor(g.Wave), g.Player.Y)
+
+	return g
+}
+
+// Step advances the game one tick. Everything that affects the score happens
+// here, driven only by input and the seeded random source, so a replay of the
+// inputs reproduces the game exactly.
+func (g *Game) Step(input PlayerInput) error {
+	// Check death timer first
+	if g.playerDead {
+		g.deathTimer.Update()
+		if g.deathTimer.IsDone() {
+			if g.Lives <= 0 {
+				// Game over - stop UFO sound and transition to end screen
+				g.endGame()
+				return nil
+			} else {
+				// Player has lives remaining - respawn
+				g.playerDead = false
+				// Reset player position to center bottom
+				playerWidth := g.Player.Sprite.Bounds().Dx()
+				g.Player.X = (Width - playerWidth) / 2
+			}
+		}
+		// Don't process other game logic while player is dead
+		return nil
+	}
+
+	if g.options.MarchMode == MarchRipple {
+		// One alien steps per frame, so the fleet speeds up as it thins out
+		g.rippleAliens()
+	} else {
+		if !g.timer.IsRunning() {
+			g.timer.Start()
+		}
+		g.timer.Update()
+		if g.timer.IsDone() {
+			// This is when we animate and Move
+			g.moveAliens()
+			g.timer = NewTimer(fleetTempo(len(g.Aliens)))
+			g.timer.Start()
+		}
+	}
+
+	// Aliens whose frames have durations animate between march steps too
+	for _, alien := range g.Aliens {
+		alien.Animate()
+	}
+
+	// Check for lose condition (aliens reaching bottom)
+	if len(g.Aliens) > 0 {
+		for _, alien := range g.Aliens {
+			if alien.Hitbox().Max.Y >= Height {
+				// Immediate transition for aliens reaching bottom
+				g.endGame()
+				return nil // Transitioning, no more updates for this scene
+			}
+		}
+	}
+
+	g.Combo.Update()
+	missed := g.Player.ShotsMissed
+	if err := g.Player.Update(input, g.sounds); err != nil {
+		return err
+	}
+	if g.Player.ShotsMissed > missed {
+		g.Combo.Miss()
+	}
+
+	// Check for missile-alien collisions
+	g.CheckPlayerMissileCollision()
+	g.checkBonusLife()
+
+	// Check for alien missile-player collisions
+	g.CheckAlienMissilePlayerCollision()
+
+	// Check for missile-base collisions
+	g.CheckMissileBaseCollisions()
+
+	// Check for aliens marching into bases
+	g.CheckAlienBaseCollisions()
+	for _, base := range g.Bases {
+		base.Update()
+	}
+
+	// Update UFO system
+	g.UpdateUFO()
+
+	// Check if the spawn rules allow a UFO
+	if g.canSpawnUFO() {
+		g.SpawnUFO()
+	}
+
+	if g.UFOScore != nil {
+		if g.UFOScore.Ticks--; g.UFOScore.Ticks <= 0 {
+			g.UFOScore = nil
+		}
+	}
+
+	// Update UFO timer
+	if g.ufoTimer != nil {
+		g.ufoTimer.Update()
+		if g.ufoTimer.IsDone() {
+			g.ufoTimer.Stop()
+			g.ufoTimer = nil
+		}
+	}
+
+	g.CheckWaveStatus()
+	g.waveTimer.Update()
+	if g.waveTimer.IsDone() {
+		g.waveTimer.Stop()
+		g.Aliens = SpawnAlienWave()
+		g.Wave++
+		g.waveShots = g.Player.ShotsFired
+		g.waveHits = 0
+
+		// Waves with their own shield layout get fresh bunkers
+		if layout, ok := assets.ShieldLayouts[g.Wave]; ok {
+			g.Bases = CreateBases(layout, g.Player.Y)
+		}
+	}
+
+	// Update alien missiles
+	activeAlienMissiles := make([]*AlienMissile, 0, len(g.AlienMissiles))
+	for _, missile := range g.AlienMissiles {
+		missile.Y += 1          // Move missile down at speed 1
+		if missile.Y < Height { // Keep missile if still on screen
+			activeAlienMissiles = append(activeAlienMissiles, missile)
+		}
+	}
+	g.AlienMissiles = activeAlienMissiles
+
+	return nil
+}
+
+// FleetIntensity rates how dangerous the wave has become, from 0 for a fresh
+// wave to 1 for a nearly cleared or nearly landed one. Gameplay music follows it.
+func (g *Game) FleetIntensity() float64 {
+	if len(g.Aliens) == 0 {
+		return 0
+	}
+
+	thinned := 1 - float64(len(g.Aliens))/float64(ALIENS_PER_WAVE)
+
+	lowest := 0
+	for _, alien := range g.Aliens {
+		lowest = max(lowest, alien.Y)
+	}
+	startY := ALIEN_SIZE * 5 // Bottom row of a fresh wave
+	descended := float64(lowest-startY) / float64(g.Player.Y-startY)
+
+	return math.Max(0, math.Min(1, math.Max(thinned, descended)))
+}
+
+// endGame stops the UFO sound and marks the game over, ready for the end screen.
+func (g *Game) endGame() {
+	if g.ufoSound != nil {
+		g.sounds.StopLoop(g.ufoSound)
+		g.ufoSound = nil
+	}
+	g.Over = true
+}
+
+// checkBonusLife awards an extra life for each milestone the score has passed.
+func (g *Game) checkBonusLife() {
+	if g.LivesFlash > 0 {
+		g.LivesFlash--
+	}
+	for g.nextBonusLife > 0 && g.Player.Points >= g.nextBonusLife {
+		g.Lives++
+		g.nextBonusLife = g.options.BonusLife.Next(g.nextBonusLife)
+		g.LivesFlash = bonusLifeFlashTicks
+		g.sounds.PlayPCM(synthBonusLifeJingle(g.sounds.SampleRate()))
+	}
+}
+
+func (g *Game) CheckWaveStatus() {
+	if len(g.Aliens) == 0 && !g.waveTimer.IsRunning() {
+		g.sounds.Play(SoundWaveClear)
+		g.restoreBases()
+		g.waveTimer.Reset()
+		g.waveTimer.Start()
+	}
+}
+
+func toggleDirection(current Direction) Direction {
+	if current == LEFT {
+		return RIGHT
+	}
+	return LEFT
+}
+
+func (g *Game) moveAliens() {
+	g.playMarchNote()
+
+	// Check if any alien will hit the screen boundaries
+	shouldReverse := false
+	for _, alien := range g.Aliens {
+		if alienAtEdge(alien, g.currentDirection) {
+			shouldReverse = true
+			break
+		}
+	}
+
+	// If we need to reverse direction, do it and move down
+	if shouldReverse {
+		g.currentDirection = toggleDirection(g.currentDirection)
+		for _, alien := range g.Aliens {
+			alien.Y += ALIEN_STEP // Move down when reversing direction
+			alien.ToggleFrame()   // Toggle animation frame
+		}
+	} else {
+		// Move aliens horizontally
+		for _, alien := range g.Aliens {
+			if g.currentDirection == LEFT {
+				alien.X -= ALIEN_STEP
+			} else {
+				alien.X += ALIEN_STEP
+			}
+			alien.ToggleFrame() // Toggle animation frame
+		}
+	}
+
+	g.fireAlienMissiles()
+}
+
+// playMarchNote plays the next note of the march loop, pitched and timed to
+// the current fleet tempo.
+func (g *Game) playMarchNote() {
+	pcm := synthMarchNote(g.sounds.SampleRate(), g.marchNote, fleetTempo(len(g.Aliens)))
+	g.marchNote = (g.marchNote + 1) % len(marchNotes)
+
+	g.sounds.PlayPCM(pcm)
+}
+
+func (g *Game) fireAlienMissiles() {
+	// Check for SquidAlien shooting (10% chance per movement)
+	for _, alien := range g.Aliens {
+		// Only allow shooting if we have less than 3 missiles active
+		if alien.AlienType == SquidAlien && g.rng.Float64() < 0.1 && len(g.AlienMissiles) < 3 {
+			// Create new alien missile
+			hitbox := alien.Hitbox()
+			missileX := hitbox.Min.X + hitbox.Dx()/2 - assets.AlienShot.Bounds().Dx()/2
+			missileY := hitbox.Max.Y
+
+			newAlienMissile := &AlienMissile{
+				Sprite: assets.AlienShot,
+				X:      missileX,
+				Y:      missileY,
+			}
+			g.AlienMissiles = append(g.AlienMissiles, newAlienMissile)
+		}
+	}
+}
+
+func (g *Game) CheckPlayerMissileCollision() {
+	activeMissiles := make([]*PlayerMissile, 0, len(g.Player.Missiles))
+	activeAliens := make([]*Alien, 0, len(g.Aliens))
+
+	// Track which aliens were hit
+	aliensHit := make(map[*Alien]bool)
+
+	for _, missile := range g.Player.Missiles {
+		hit := false
+		missilePos := image.Pt(missile.X, missile.Y)
+
+		for _, alien := range g.Aliens {
+			// Skip if this alien was already hit
+			if aliensHit[alien] {
+				continue
+			}
+
+			// Check if the missile's pixels touch the alien's
+			if alienHit(missile.Sprite, missilePos, alien) {
+				alienRect := alien.Hitbox()
+
+				// Add alien points to player, multiplied by the combo
+				g.Player.Points += alien.PointsValue * g.Combo.Multiplier()
+				g.Combo.Hit()
+				hit = true
+				aliensHit[alien] = true
+				g.waveHits++
+
+				// Play alien explosion sound
+				g.sounds.PlayAt(SoundAlienExplosion, rectCenterX(alienRect))
+
+				break // This missile hit an alien, don't check other aliens
+			}
+		}
+
+		// Check UFO collision
+		if !hit && g.UFO != nil {
+			// Check if the missile's pixels touch the UFO's
+			if spritesOverlap(missile.Sprite, missilePos, g.UFO.Sprite, image.Pt(g.UFO.X, g.UFO.Y)) {
+				hit = true
+
+				// Shots glancing off armor neither build the combo nor count
+				// towards the wave's accuracy, only the one that brings it down
+				if g.hitUFO(missile) {
+					g.waveHits++
+					g.Combo.Hit()
+				}
+			}
+		}
+
+		// Only keep missile if it didn't hit anything
+		if !hit {
+			activeMissiles = append(activeMissiles, missile)
+		}
+	}
+
+	// Build active aliens list (only aliens that weren't hit)
+	for _, alien := range g.Aliens {
+		if !aliensHit[alien] {
+			activeAliens = append(activeAliens, alien)
+		}
+	}
+
+	// Update the slices with only active (non-collided) objects
+	g.Player.Missiles = activeMissiles
+	g.Aliens = activeAliens
+}
+
+func (g *Game) CheckAlienMissilePlayerCollision() {
+	// Don't check collisions if player is already dead
+	if g.playerDead {
+		return
+	}
+
+	activeAlienMissiles := make([]*AlienMissile, 0, len(g.AlienMissiles))
+
+	// Get player bounds
+	playerPos := image.Pt(g.Player.X, g.Player.Y)
+	playerRect := image.Rect(g.Player.X, g.Player.Y,
+		g.Player.X+g.Player.Sprite.Bounds().Dx(),
+		g.Player.Y+g.Player.Sprite.Bounds().Dy())
+
+	for _, missile := range g.AlienMissiles {
+		// Check if the missile's pixels touch the player's
+		if spritesOverlap(missile.Sprite, image.Pt(missile.X, missile.Y), g.Player.Sprite, playerPos) {
+			// Player is hit - decrease lives, break the combo and start death timer
+			g.Lives--
+			g.Combo.Reset()
+			g.playerDead = true
+			g.deathTimer.Reset()
+			g.deathTimer.Start()
+
+			// Clear all alien missiles to prevent instant death on respawn
+			g.AlienMissiles = make([]*AlienMissile, 0)
+
+			// Play player death sound
+			g.sounds.PlayAt(SoundPlayerDeath, rectCenterX(playerRect))
+
+			// Return early since we cleared all missiles
+			return
+		} else {
+			// Keep missile if no collision
+			activeAlienMissiles = append(activeAlienMissiles, missile)
+		}
+	}
+
+	// Update alien missiles slice
+	g.AlienMissiles = activeAlienMissiles
+}
+
+func (g *Game) CheckMissileBaseCollisions() {
+	// Check player missiles vs bases
+	activeMissiles := make([]*PlayerMissile, 0, len(g.Player.Missiles))
+	for _, missile := range g.Player.Missiles {
+		if !g.blastBases(missile.Sprite, image.Pt(missile.X, missile.Y), true) {
+			activeMissiles = append(activeMissiles, missile)
+		} else {
+			g.Combo.Miss() // Shooting your own shields breaks the chain
+		}
+	}
+	g.Player.Missiles = activeMissiles
+
+	// Check alien missiles vs bases
+	activeAlienMissiles := make([]*AlienMissile, 0, len(g.AlienMissiles))
+	for _, missile := range g.AlienMissiles {
+		if !g.blastBases(missile.Sprite, image.Pt(missile.X, missile.Y), false) {
+			activeAlienMissiles = append(activeAlienMissiles, missile)
+		}
+	}
+	g.AlienMissiles = activeAlienMissiles
+}
+
+// blastBases checks a shot against every base. On a hit it blows a crater
+// where the shot first touched and reports true so the shot is removed.
+func (g *Game) blastBases(shot *assets.Sprite, pos image.Point, movingUp bool) bool {
+	crater := alienShotCrater
+	if movingUp {
+		crater = playerShotCrater
+	}
+
+	for _, base := range g.Bases {
+		impact, hit := base.Impact(shot.Mask, pos, movingUp)
+		if !hit {
+			continue
+		}
+		base.Blast(crater, impact)
+
+		// Play alien explosion sound for base hit
+		g.sounds.PlayAt(SoundAlienExplosion, float64(impact.X))
+		return true
+	}
+	return false
+}
+
+func (g *Game) CheckAlienBaseCollisions() {
+	activeAliens := make([]*Alien, 0, len(g.Aliens))
+	for _, alien := range g.Aliens {
+		zapped := false
+		for _, base := range g.Bases {
+			// Each material reacts in its own way to an alien marching into it
+			if base.Touch(alien.Mask(), alien.DrawPosition()) {
+				zapped = true
+			}
+		}
+
+		if zapped {
+			// Energy shields destroy aliens outright, but score nothing
+			g.sounds.PlayAt(SoundAlienExplosion, rectCenterX(alien.Hitbox()))
+			continue
+		}
+		activeAliens = append(activeAliens, alien)
+	}
+	g.Aliens = activeAliens
+}
+
+// restoreBases repairs the shields at the end of a wave, as the restore
+// setting says. A partial repair matches the player's accuracy this wave.
+func (g *Game) restoreBases() {
+	share := 0.0
+	switch g.options.ShieldRestore {
+	case RestoreFully:
+		share = 1
+	case RestorePartially:
+		if shots := g.Player.ShotsFired - g.waveShots; shots > 0 {
+			share = math.Min(1, float64(g.waveHits)/float64(shots))
+		}
+	}
+
+	if share > 0 {
+		for _, base := range g.Bases {
+			base.Restore(share)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package game
+
+// PlayerInput is what the player did on one tick, as bit flags.
+type PlayerInput uint8
+
+const (
+	InputLeft PlayerInput = 1 << iota
+	InputRight
+	InputFire // Fire was pressed this tick, not just held
+)
@@ -0,0 +1,95 @@
+package game
+
+import "sort"
+
+// MarchMode selects how the alien fleet advances.
+type MarchMode int
+
+const (
+	MarchFleet  MarchMode = iota // The whole fleet steps together on each timer tick
+	MarchRipple                  // One alien steps per frame, bottom-left to top-right
+)
+
+const ALIEN_STEP = 8 // Pixels an alien moves per step, across or down
+
+// alienAtEdge reports whether the alien's next step in dir would take its
+// hitbox to the screen boundary.
+func alienAtEdge(alien *Alien, dir Direction) bool {
+	hitbox := alien.Hitbox()
+	if dir == LEFT {
+		return hitbox.Min.X-ALIEN_STEP <= 0
+	}
+	return hitbox.Max.X+ALIEN_STEP >= Width
+}
+
+// rippleAliens steps a single alien, the way the arcade hardware did. A full
+// pass over the fleet is one fleet step, so fewer survivors means a faster march.
+func (g *Game) rippleAliens() {
+	if len(g.Aliens) == 0 {
+		g.marchQueue = nil
+		return
+	}
+
+	for {
+		if len(g.marchQueue) == 0 {
+			g.startMarchPass()
+		}
+
+		alien := g.marchQueue[0]
+		g.marchQueue = g.marchQueue[1:]
+
+		// Aliens shot down mid-pass are skipped
+		if containsAlien(g.Aliens, alien) {
+			g.stepAlien(alien)
+			return
+		}
+	}
+}
+
+// startMarchPass queues every alien for the next pass and decides whether the
+// pass steps across or down, based on what happened during the previous one.
+func (g *Game) startMarchPass() {
+	g.marchDown = g.marchReverse
+	if g.marchReverse {
+		g.currentDirection = toggleDirection(g.currentDirection)
+		g.marchReverse = false
+	}
+
+	g.marchQueue = make([]*Alien, len(g.Aliens))
+	copy(g.marchQueue, g.Aliens)
+	sort.Slice(g.marchQueue, func(i, j int) bool {
+		a, b := g.marchQueue[i], g.marchQueue[j]
+		if a.Y != b.Y {
+			return a.Y > b.Y // Bottom row first
+		}
+		return a.X < b.X // Left to right within a row
+	})
+
+	g.playMarchNote()
+	g.fireAlienMissiles()
+}
+
+func (g *Game) stepAlien(alien *Alien) {
+	if g.marchDown {
+		alien.Y += ALIEN_STEP
+	} else if g.currentDirection == LEFT {
+		alien.X -= ALIEN_STEP
+	} else {
+		alien.X += ALIEN_STEP
+	}
+	alien.ToggleFrame()
+
+	// Reverse once the pass completes so the rest of the fleet catches up first
+	if !g.marchDown && alienAtEdge(alien, g.currentDirection) {
+		g.marchReverse = true
+	}
+}
+
+func containsAlien(aliens []*Alien, target *Alien) bool {
+	for _, alien := range aliens {
+		if alien == target {
+			return true
+		}
+	}
+	return false
+}
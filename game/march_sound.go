@@ -0,0 +1,73 @@
+package game
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// marchNotes is the descending four-note bass loop the fleet marches to, in Hz.
+var marchNotes = [4]float64{98.00, 87.31, 82.41, 73.42}
+
+const (
+	alienTempoPerAlien = 20 * time.Millisecond                // Fleet step time contributed by each alien
+	fullFleetTempo     = ALIENS_PER_WAVE * alienTempoPerAlien // Tempo of a freshly spawned wave
+	minMarchNoteLength = 40 * time.Millisecond
+	maxMarchNoteLength = 150 * time.Millisecond
+)
+
+// fleetTempo returns the time between fleet steps for the given number of aliens.
+func fleetTempo(aliens int) time.Duration {
+	return time.Duration(aliens) * alienTempoPerAlien
+}
+
+// marchNoteFrequency returns the pitch of a note in the loop, raised as the
+// fleet speeds up so the march gets more frantic along with the tempo.
+func marchNoteFrequency(note int, tempo time.Duration) float64 {
+	urgency := 1 - float64(tempo)/float64(fullFleetTempo)
+	urgency = math.Max(0, math.Min(1, urgency))
+	return marchNotes[note%len(marchNotes)] * (1 + 0.5*urgency)
+}
+
+// marchNoteLength returns how long a note rings, half the step time so
+// consecutive notes never overlap.
+func marchNoteLength(tempo time.Duration) time.Duration {
+	length := tempo / 2
+	if length < minMarchNoteLength {
+		return minMarchNoteLength
+	}
+	if length > maxMarchNoteLength {
+		return maxMarchNoteLength
+	}
+	return length
+}
+
+// synthMarchNote renders one note of the march as 16-bit stereo PCM, the
+// format audio.Context players expect.
+func synthMarchNote(sampleRate, note int, tempo time.Duration) []byte {
+	freq := marchNoteFrequency(note, tempo)
+	samples := int(marchNoteLength(tempo).Seconds() * float64(sampleRate))
+
+	pcm := make([]byte, samples*4)
+	for i := 0; i < samples; i++ {
+		t := float64(i) / float64(sampleRate)
+
+		// Square wave for the arcade buzz, with a quick decay so it thumps
+		value := 1.0
+		if math.Sin(2*math.Pi*freq*t) < 0 {
+			value = -1.0
+		}
+		envelope := math.Exp(-5 * float64(i) / float64(samples))
+		sample := int16(value * envelope * 0.3 * math.MaxInt16)
+
+		PutStereoSample(pcm, i, sample)
+	}
+	return pcm
+}
+
+// PutStereoSample writes the same 16-bit sample to both channels of frame i
+// of 16-bit stereo PCM, the format every generated sound and song uses.
+func PutStereoSample(pcm []byte, i int, sample int16) {
+	binary.LittleEndian.PutUint16(pcm[i*4:], uint16(sample))
+	binary.LittleEndian.PutUint16(pcm[i*4+2:], uint16(sample))
+}
@@ -0,0 +1,36 @@
+package game
+
+import (
+	"image"
+	"invaders/assets"
+	"testing"
+)
+
+func TestAlienAtEdge(t *testing.T) {
+	// A 16px frame whose hitbox leaves 4px of empty space on either side
+	sheet := &assets.Sheet{Frames: []assets.Frame{{
+		Rect:   image.Rect(0, 0, 16, 16),
+		Hitbox: image.Rect(4, 0, 12, 16),
+	}}}
+
+	tests := []struct {
+		name string
+		x    int
+		dir  Direction
+		want bool
+	}{
+		{"clear of the right edge", Width - 12 - ALIEN_STEP - 1, RIGHT, false},
+		{"hitbox reaches the right edge", Width - 12 - ALIEN_STEP, RIGHT, true},
+		{"frame past the right edge but hitbox inside", Width - 16 - ALIEN_STEP + 1, RIGHT, false},
+		{"clear of the left edge", ALIEN_STEP - 4 + 1, LEFT, false},
+		{"hitbox reaches the left edge", ALIEN_STEP - 4, LEFT, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alien := &Alien{Sheet: sheet, X: tt.x, Y: 100}
+			if got := alienAtEdge(alien, tt.dir); got != tt.want {
+				t.Errorf("alienAtEdge at x %d = %v, want %v", tt.x, got, tt.want)
+			}
+		})
+	}
+}
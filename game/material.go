@@ -0,0 +1,132 @@
+package game
+
+import (
+	"image/color"
+	"invaders/assets"
+)
+
+// AlienContact is what a shield material does when an alien marches into it.
+type AlienContact int
+
+const (
+	ContactCrumble AlienContact = iota // The shield is wiped out under the alien
+	ContactHold                        // The alien passes over without harming the shield
+	ContactZap                         // The alien is destroyed and drains the shield where it touched
+)
+
+// energyRechargeTicks is how often damaged energy shield pixels regain a hit point.
+const energyRechargeTicks = 90
+
+// Material is what a shield pixel is made of.
+type Material struct {
+	Name     string
+	Cell     rune // Character that marks the material in shield layout files
+	HP       int  // Hits from a crater before a pixel is destroyed
+	Contact  AlienContact
+	Recharge bool                 // Damaged pixels slowly heal
+	Image    string               // Sheet the sprites are cut from, for hot reload
+	Sprites  func() *assets.Sheet // Texture per damage level, from undamaged to nearly destroyed
+}
+
+var (
+	MaterialBrick = &Material{
+		Name:    "Brick",
+		Cell:    '#',
+		HP:      2,
+		Contact: ContactCrumble,
+		Image:   "player/base.png",
+		Sprites: func() *assets.Sheet { return assets.BaseSprites },
+	}
+	MaterialSteel = &Material{
+		Name:    "Steel",
+		Cell:    'S',
+		HP:      4,
+		Contact: ContactHold,
+		Image:   "player/steel.png",
+		Sprites: func() *assets.Sheet { return assets.SteelSprites },
+	}
+	MaterialEnergy = &Material{
+		Name:     "Energy",
+		Cell:     'E',
+		HP:       2,
+		Contact:  ContactZap,
+		Recharge: true,
+		Image:    "player/energy.png",
+		Sprites:  func() *assets.Sheet { return assets.EnergySprites },
+	}
+)
+
+var materials = []*Material{MaterialBrick, MaterialSteel, MaterialEnergy}
+
+// materialsVersion is bumped whenever a material's sheet is reloaded, which
+// recolors every base without any of their pixels changing.
+var materialsVersion int
+
+// ReloadMaterial tells bases that an image was reloaded, so any drawn with a
+// material cut from it are redrawn.
+func ReloadMaterial(imagePath string) {
+	for _, m := range materials {
+		if m.Image == imagePath {
+			materialsVersion++
+			return
+		}
+	}
+}
+
+// materialForCell returns the material a layout character stands for.
+func materialForCell(cell rune) *Material {
+	for _, m := range materials {
+		if m.Cell == cell {
+			return m
+		}
+	}
+	return MaterialBrick
+}
+
+// Color returns the color of a shield pixel at base-local x, y with hp left.
+// Shield pixels are drawn at half the scale of the damage sprites.
+func (m *Material) Color(x, y, hp int) color.Color {
+	sprites := m.Sprites()
+	if sprites == nil || len(sprites.Frames) == 0 {
+		return baseColor
+	}
+
+	frame := (m.HP - hp) * len(sprites.Frames) / m.HP
+	pixels := sprites.FrameImage(min(frame, len(sprites.Frames)-1))
+
+	bounds := pixels.Bounds()
+	return pixels.At(bounds.Min.X+(x*2)%bounds.Dx(), bounds.Min.Y+(y*2)%bounds.Dy())
+}
+
+// ShieldRestore says how shields are repaired when a wave is cleared.
+type ShieldRestore int
+
+const (
+	RestoreNever     ShieldRestore = iota
+	RestoreFully                   // Rebuild every base as new
+	RestorePartially               // Rebuild a share of the damage equal to the wave's accuracy bonus
+	ShieldRestoreCount
+)
+
+func (r ShieldRestore) String() string {
+	switch r {
+	case RestoreFully:
+		return "Fully"
+	case RestorePartially:
+		return "Bonus"
+	default:
+		return "Never"
+	}
+}
+
+// restoreDither orders a base's pixels for partial restoration, so a 50%
+// repair fills in an even checkerboard rather than a solid block.
+func restoreDither(x, y int) float64 {
+	bayer := [4][4]int{
+		{0, 8, 2, 10},
+		{12, 4, 14, 6},
+		{3, 11, 1, 9},
+		{15, 7, 13, 5},
+	}
+	return (float64(bayer[y%4][x%4]) + 0.5) / 16
+}
@@ -0,0 +1,30 @@
+package game
+
+import (
+	"invaders/assets"
+	"testing"
+)
+
+func TestReloadMaterial(t *testing.T) {
+	tests := []struct {
+		image       string
+		wantRedrawn bool
+	}{
+		{"player/base.png", true},
+		{"player/steel.png", true},
+		{"player/energy.png", true},
+		{"player/Player.png", false},
+		{"invaders/topInvader.png", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			base := NewBase(assets.ShieldLayoutFor(1), 0, 0)
+			version := base.Version()
+
+			ReloadMaterial(tt.image)
+			if redrawn := base.Version() != version; redrawn != tt.wantRedrawn {
+				t.Errorf("base redrawn = %v, want %v", redrawn, tt.wantRedrawn)
+			}
+		})
+	}
+}
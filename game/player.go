@@ -0,0 +1,112 @@
+package game
+
+import (
+	"invaders/assets"
+	"time"
+)
+
+const (
+	playerSpeed         = 2
+	playerMissileSpeed  = 3
+	playerShootCooldown = 500 * time.Millisecond // Cooldown for shooting
+)
+
+type PlayerMissile struct {
+	Sprite *assets.Sprite
+	X      int
+	Y      int
+	Shot   int // Which shot of the game this is, counting from 1
+}
+
+type AlienMissile struct {
+	Sprite *assets.Sprite
+	X      int
+	Y      int
+}
+
+type Player struct {
+	Sprite      *assets.Sprite
+	X           int
+	Y           int
+	ShootTimer  *Timer
+	Missiles    []*PlayerMissile // Slice to hold active missiles
+	Points      int
+	ShotsFired  int // Missiles fired this game
+	ShotsMissed int // Missiles that flew off the top of the screen
+}
+
+func NewPlayer() *Player {
+	playerWidth := assets.Player.Bounds().Dx()
+	playerHeight := assets.Player.Bounds().Dy()
+	return &Player{
+		Sprite:     assets.Player,
+		X:          (Width - playerWidth) / 2,
+		Y:          Height - playerHeight - 8, // 8 pixels from the bottom
+		ShootTimer: NewTimer(playerShootCooldown),
+		Missiles:   make([]*PlayerMissile, 0), // Initialize missile slice
+		Points:     0,
+	}
+}
+
+func NewPlayerMissile(p *Player) *PlayerMissile {
+	// Center missile on player
+	missileWidth := assets.PlayerShot.Bounds().Dx()
+	playerWidth := p.Sprite.Bounds().Dx()
+	return &PlayerMissile{
+		Sprite: assets.PlayerShot,
+		X:      p.X + (playerWidth / 2) - (missileWidth / 2),
+		Y:      p.Y,
+		Shot:   p.ShotsFired + 1,
+	}
+}
+
+// Update applies one tick of input and plays effects through sounds
+func (p *Player) Update(input PlayerInput, sounds Sounds) error {
+	// Player movement
+	if input&InputLeft != 0 {
+		p.X -= playerSpeed
+	}
+	if input&InputRight != 0 {
+		p.X += playerSpeed
+	}
+
+	// Keep player within screen bounds
+	playerSpriteWidth := p.Sprite.Bounds().Dx()
+	if p.X < 0 {
+		p.X = 0
+	}
+	if p.X+playerSpriteWidth > Width {
+		p.X = Width - playerSpriteWidth
+	}
+
+	// Shooting logic
+	p.ShootTimer.Update()
+	if input&InputFire != 0 {
+		if !p.ShootTimer.IsRunning() || p.ShootTimer.IsDone() {
+			newMissile := NewPlayerMissile(p)
+			p.Missiles = append(p.Missiles, newMissile)
+			p.ShotsFired++
+			p.ShootTimer.Reset() // Reset and start the timer
+			p.ShootTimer.Start()
+
+			// Play shoot sound
+			if sounds != nil {
+				sounds.PlayAt(SoundPlayerShoot, float64(newMissile.X+newMissile.Sprite.Bounds().Dx()/2))
+			}
+		}
+	}
+
+	// Update missiles
+	activeMissiles := make([]*PlayerMissile, 0, len(p.Missiles))
+	for _, missile := range p.Missiles {
+		missile.Y -= playerMissileSpeed
+		if missile.Y+missile.Sprite.Bounds().Dy() > 0 { // Check if missile is still on screen (top edge)
+			activeMissiles = append(activeMissiles, missile)
+		} else {
+			p.ShotsMissed++
+		}
+	}
+	p.Missiles = activeMissiles
+
+	return nil
+}
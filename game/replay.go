@@ -0,0 +1,103 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"invaders/assets"
+)
+
+// Replay records a game's input so it can be re-simulated to check its score.
+// Together with the seed, it holds everything that makes one game differ from
+// another; the rules themselves come from the loaded assets.
+type Replay struct {
+	Options
+	Pack   string     `json:"pack,omitempty"` // assets.PackID of the content pack played with
+	Dev    bool       `json:"dev,omitempty"`  // Assets were hot reloaded, so the game can't be re-simulated
+	Inputs []inputRun `json:"inputs"`         // Run-length encoded, one entry per change of input
+}
+
+// inputRun is an input held for a number of ticks, encoded as [input, ticks].
+type inputRun [2]int
+
+func NewReplay(options Options) *Replay {
+	return &Replay{Options: options, Pack: assets.PackID()}
+}
+
+// Record appends one tick of input.
+func (r *Replay) Record(input PlayerInput) {
+	if n := len(r.Inputs); n > 0 && r.Inputs[n-1][0] == int(input) {
+		r.Inputs[n-1][1]++
+		return
+	}
+	r.Inputs = append(r.Inputs, inputRun{int(input), 1})
+}
+
+// maxReplayTicks caps re-simulation at two hours of play.
+const maxReplayTicks = 2 * 60 * 60 * TicksPerSecond
+
+// Simulate re-runs a replayed game from its seed, without sound, and returns
+// the final score and wave. The game must end exactly on the last recorded
+// tick. Assets must be loaded first, from the content pack the replay was
+// recorded with.
+func Simulate(seed int64, replay *Replay) (points, wave int, err error) {
+	if replay.Dev {
+		return 0, 0, errors.New("assets were hot reloaded during the game")
+	}
+	if replay.Pack != assets.PackID() {
+		return 0, 0, fmt.Errorf("played with %s, not %s", packName(replay.Pack), packName(assets.PackID()))
+	}
+
+	g := New(seed, replay.Options, silence{})
+
+	ticks := 0
+	for i, run := range replay.Inputs {
+		if run[1] < 1 || run[0] < 0 || PlayerInput(run[0])&^(InputLeft|InputRight|InputFire) != 0 {
+			return 0, 0, fmt.Errorf("input %d is invalid", i)
+		}
+		for range run[1] {
+			if g.Over {
+				return 0, 0, errors.New("replay continues after the game ended")
+			}
+			if ticks++; ticks > maxReplayTicks {
+				return 0, 0, errors.New("replay is too long")
+			}
+			if err := g.Step(PlayerInput(run[0])); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	if !g.Over {
+		return 0, 0, errors.New("replay ends before the game is over")
+	}
+	return g.Player.Points, g.Wave, nil
+}
+
+// packName describes a content pack ID for error messages.
+func packName(id string) string {
+	if id == "" {
+		return "the built-in assets"
+	}
+	return fmt.Sprintf("content pack %q", id)
+}
+
+// VerifyScore checks a claimed score and wave by re-simulating the game from
+// its seed and JSON-encoded replay, as a leaderboard server does.
+func VerifyScore(seed int64, data []byte, points, wave int) error {
+	if len(data) == 0 {
+		return errors.New("no replay")
+	}
+	var replay Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+
+	replayPoints, replayWave, err := Simulate(seed, &replay)
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	if replayPoints != points || replayWave != wave {
+		return fmt.Errorf("replay scores %d on wave %d, not %d on wave %d", replayPoints, replayWave, points, wave)
+	}
+	return nil
+}
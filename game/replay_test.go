@@ -0,0 +1,182 @@
+package game
+
+import (
+	"encoding/json"
+	"invaders/assets"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	if err := assets.Load(); err != nil {
+		log.Fatalf("Error loading assets: %v", err)
+	}
+	os.Exit(m.Run())
+}
+
+// playGame plays a game to the end on random input, recording it as a player would.
+func playGame(t *testing.T, seed int64, options Options) (*Game, *Replay) {
+	t.Helper()
+
+	inputs := rand.New(rand.NewSource(seed))
+	g := New(seed, options, silence{})
+	replay := NewReplay(options)
+	for !g.Over {
+		// Hold each input for a few ticks, as fingers on keys do
+		input := PlayerInput(inputs.Intn(8))
+		for range 1 + inputs.Intn(20) {
+			if g.Over {
+				break
+			}
+			replay.Record(input)
+			if err := g.Step(input); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return g, replay
+}
+
+// submission is what a player sends a leaderboard server for a finished game.
+type submission struct {
+	Seed   int64
+	Replay []byte
+	Points int
+	Wave   int
+}
+
+func (s submission) verify() error {
+	return VerifyScore(s.Seed, s.Replay, s.Points, s.Wave)
+}
+
+// submissionOf builds the submission for a finished game.
+func submissionOf(t *testing.T, g *Game, replay *Replay) submission {
+	t.Helper()
+
+	data, err := json.Marshal(replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return submission{Seed: g.Seed, Replay: data, Points: g.Player.Points, Wave: g.Wave}
+}
+
+func TestSimulateRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		seed    int64
+		options Options
+	}{
+		{"fleet", 1, Options{BonusLife: BonusLife{At: 1500}}},
+		{"ripple", 2, Options{MarchMode: MarchRipple}},
+		{"restore", 3, Options{ShieldRestore: RestorePartially, BonusLife: BonusLife{At: 500, Every: 500}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, replay := playGame(t, tt.seed, tt.options)
+
+			// Go through JSON, as a submission does
+			var decoded Replay
+			if err := json.Unmarshal(submissionOf(t, g, replay).Replay, &decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			points, wave, err := Simulate(tt.seed, &decoded)
+			if err != nil {
+				t.Fatalf("Simulate: %v", err)
+			}
+			if points != g.Player.Points || wave != g.Wave {
+				t.Errorf("Simulate = %d on wave %d, played %d on wave %d", points, wave, g.Player.Points, g.Wave)
+			}
+		})
+	}
+}
+
+func TestVerifyScore(t *testing.T) {
+	g, replay := playGame(t, 4, Options{})
+	if g.Player.Points == 0 {
+		t.Fatal("test game scored nothing, so tampering can't be told apart")
+	}
+
+	if err := submissionOf(t, g, replay).verify(); err != nil {
+		t.Fatalf("honest score rejected: %v", err)
+	}
+
+	noReplay := submissionOf(t, g, replay)
+	noReplay.Replay = nil
+	if err := noReplay.verify(); err == nil {
+		t.Error("VerifyScore accepted a score without a replay")
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(score *submission, replay *Replay)
+		wantErr string
+	}{
+		{
+			name:    "tampered score",
+			tamper:  func(score *submission, replay *Replay) { score.Points += 10 },
+			wantErr: "replay scores",
+		},
+		{
+			name:    "tampered wave",
+			tamper:  func(score *submission, replay *Replay) { score.Wave++ },
+			wantErr: "replay scores",
+		},
+		{
+			name:    "other seed",
+			tamper:  func(score *submission, replay *Replay) { score.Seed++ },
+			wantErr: "replay",
+		},
+		{
+			name: "truncated replay",
+			tamper: func(score *submission, replay *Replay) {
+				replay.Inputs = replay.Inputs[:len(replay.Inputs)/2]
+			},
+			wantErr: "ends before the game is over",
+		},
+		{
+			name: "trailing input",
+			tamper: func(score *submission, replay *Replay) {
+				replay.Inputs = append(replay.Inputs, inputRun{int(InputFire), 1})
+			},
+			wantErr: "continues after the game ended",
+		},
+		{
+			name: "invalid input",
+			tamper: func(score *submission, replay *Replay) {
+				replay.Inputs[0] = inputRun{0xff, 1}
+			},
+			wantErr: "input 0 is invalid",
+		},
+		{
+			name:    "hot reloaded",
+			tamper:  func(score *submission, replay *Replay) { replay.Dev = true },
+			wantErr: "hot reloaded",
+		},
+		{
+			name:    "other pack",
+			tamper:  func(score *submission, replay *Replay) { replay.Pack = "Neon 1.0 0123456789abcdef" },
+			wantErr: `content pack "Neon 1.0 0123456789abcdef"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := *replay
+			tampered.Inputs = append([]inputRun{}, replay.Inputs...)
+			score := submissionOf(t, g, replay)
+			tt.tamper(&score, &tampered)
+			score.Replay = submissionOf(t, g, &tampered).Replay
+
+			err := score.verify()
+			if err == nil {
+				t.Fatal("VerifyScore accepted the submission")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VerifyScore = %q, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
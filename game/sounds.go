@@ -0,0 +1,46 @@
+package game
+
+// SoundID names a sound effect the game plays.
+type SoundID int
+
+const (
+	SoundPlayerShoot SoundID = iota
+	SoundAlienExplosion
+	SoundPlayerDeath
+	SoundUFO
+	SoundWaveClear
+)
+
+// Sounds plays the game's sound effects. Positions are playfield X
+// coordinates, so effects can be panned to follow what made them.
+type Sounds interface {
+	SampleRate() int
+	Play(id SoundID)
+	PlayAt(id SoundID, x float64)
+	PlayPCM(pcm []byte)
+	Loop(id SoundID, gain float64) (SoundLoop, error)
+	StopLoop(loop SoundLoop)
+}
+
+// SoundLoop is a repeating sound, such as the UFO warble, that can be panned
+// as its emitter moves.
+type SoundLoop interface {
+	SetPan(x float64)
+}
+
+// silence is Sounds for games nobody is listening to, such as re-simulated replays.
+type silence struct{}
+
+func (silence) SampleRate() int              { return 44100 }
+func (silence) Play(id SoundID)              {}
+func (silence) PlayAt(id SoundID, x float64) {}
+func (silence) PlayPCM(pcm []byte)           {}
+func (silence) StopLoop(loop SoundLoop)      {}
+
+func (silence) Loop(id SoundID, gain float64) (SoundLoop, error) {
+	return silentLoop{}, nil
+}
+
+type silentLoop struct{}
+
+func (silentLoop) SetPan(x float64) {}
@@ -0,0 +1,52 @@
+package game
+
+import "time"
+
+// TicksPerSecond is how often Step is meant to be called, matching ebiten's
+// default tick rate.
+const TicksPerSecond = 60
+
+// Timer counts ticks up to a duration. It works like the stopwatch package's
+// timers, but counts at TicksPerSecond instead of asking ebiten, so games
+// run the same without a window.
+type Timer struct {
+	currentTicks int
+	maxTicks     int
+	active       bool
+}
+
+func NewTimer(d time.Duration) *Timer {
+	return &Timer{maxTicks: int(d.Milliseconds()) * TicksPerSecond / 1000}
+}
+
+// Start begins counting, or resumes a stopped timer.
+func (t *Timer) Start() {
+	t.active = true
+}
+
+// Stop pauses the timer.
+func (t *Timer) Stop() {
+	t.active = false
+}
+
+// Update counts one tick while the timer is running.
+func (t *Timer) Update() {
+	if t.active && t.currentTicks < t.maxTicks {
+		t.currentTicks++
+	}
+}
+
+// Reset goes back to zero ticks, without starting or stopping the timer.
+func (t *Timer) Reset() {
+	t.currentTicks = 0
+}
+
+// IsDone reports whether the full duration has been counted.
+func (t *Timer) IsDone() bool {
+	return t.maxTicks <= t.currentTicks
+}
+
+// IsRunning reports whether the timer is started and not yet done.
+func (t *Timer) IsRunning() bool {
+	return !t.IsDone() && t.active
+}
@@ -0,0 +1,293 @@
+package game
+
+import (
+	"image"
+	"invaders/assets"
+	"log"
+	"math"
+	"time"
+)
+
+// UFOKind is a variant of the mystery ship.
+type UFOKind int
+
+const (
+	UFONormal  UFOKind = iota
+	UFOBomber          // Drops bombs as it crosses
+	UFOArmored         // Takes several hits to destroy
+	UFOBonus           // Crosses at double speed for triple the score
+)
+
+// ufoVariant describes how a kind of UFO behaves and when it starts showing up.
+type ufoVariant struct {
+	MinWave    int // First wave it can appear in
+	Weight     int // Relative chance of being picked
+	HP         int // Hits needed to destroy it
+	SpeedScale int // Multiplies the wave's UFO speed
+	Multiplier int // Multiplies the mystery score
+	BombTicks  int // Ticks between bombs, or 0 for none
+}
+
+var ufoVariants = map[UFOKind]ufoVariant{
+	UFONormal:  {MinWave: 1, Weight: 6, HP: 1, SpeedScale: 1, Multiplier: 1},
+	UFOBomber:  {MinWave: 2, Weight: 2, HP: 1, SpeedScale: 1, Multiplier: 1, BombTicks: 50},
+	UFOArmored: {MinWave: 3, Weight: 2, HP: 3, SpeedScale: 1, Multiplier: 2},
+	UFOBonus:   {MinWave: 2, Weight: 1, HP: 1, SpeedScale: 2, Multiplier: 3},
+}
+
+// ufoKinds lists the variants in a fixed order, so picking one with the
+// seeded rng doesn't depend on map iteration.
+var ufoKinds = []UFOKind{UFONormal, UFOBomber, UFOArmored, UFOBonus}
+
+const (
+	ufoY              = 16 // Pixels from the top of the screen
+	ufoFirstKills     = 10 // Aliens to kill in a wave before its first UFO
+	ufoMinAliens      = 8  // No UFO comes once the fleet is this thin, as in the arcade
+	ufoHitFlashTicks  = 8  // How long an armored UFO flashes when hit
+	ufoMaxSpeed       = 3  // Fastest a normal UFO gets, in pixels per tick
+	ufoWavesPerSpeed  = 3  // Waves between each speed increase
+	ufoMinDelay       = 10 // Shortest wait between UFOs, in seconds
+	ufoDelayVariation = 20 // Longest extra wait between UFOs, in seconds
+)
+
+type UFO struct {
+	Sprite    *assets.Sprite
+	Kind      UFOKind
+	X         int
+	Y         int
+	Speed     int // Pixels per tick
+	Direction int // 1 to cross left to right, -1 right to left
+	HP        int // Hits left before it's destroyed
+	BombTimer int // Ticks until the next bomb
+	HitFlash  int // Ticks left flashing after a hit that didn't destroy it
+}
+
+// ufoSpeed returns how far a UFO of the given kind moves each tick in a wave.
+func ufoSpeed(kind UFOKind, wave int) int {
+	speed := min(1+(wave-1)/ufoWavesPerSpeed, ufoMaxSpeed)
+	return speed * ufoVariants[kind].SpeedScale
+}
+
+// NewUFO creates a UFO entering from the left if direction is 1, or from the right if -1.
+func NewUFO(kind UFOKind, direction, wave int) *UFO {
+	variant := ufoVariants[kind]
+	ufo := &UFO{
+		Sprite:    assets.UFO,
+		Kind:      kind,
+		Y:         ufoY,
+		Speed:     ufoSpeed(kind, wave),
+		Direction: direction,
+		HP:        variant.HP,
+		BombTimer: variant.BombTicks,
+	}
+
+	// Start just off screen on the side it enters from
+	if direction > 0 {
+		ufo.X = -ufo.Sprite.Bounds().Dx()
+	} else {
+		ufo.X = Width
+	}
+	return ufo
+}
+
+// Center returns the x coordinate of the middle of the UFO.
+func (u *UFO) Center() int {
+	return u.X + u.Sprite.Bounds().Dx()/2
+}
+
+// OffScreen reports whether the UFO has left the screen on the side it was heading for.
+func (u *UFO) OffScreen() bool {
+	if u.Direction > 0 {
+		return u.X >= Width
+	}
+	return u.X+u.Sprite.Bounds().Dx() < 0
+}
+
+// UFOScorePopup shows what a destroyed UFO was worth where it was hit.
+type UFOScorePopup struct {
+	X, Y   int
+	Points int
+	Ticks  int // Ticks left on screen
+}
+
+const UFOScorePopupTicks = 60
+
+// ufoMysteryScores is the arcade's mystery score table. Each shot the player
+// fires steps through it, so the UFO's worth depends on the shot that hits
+// it: the 23rd shot, and every 15th after, scores 300.
+var ufoMysteryScores = [15]int{100, 50, 50, 100, 150, 100, 100, 50, 300, 100, 100, 100, 50, 150, 100}
+
+// ufoMysteryScore returns what the UFO is worth when hit by the given shot of the game.
+func ufoMysteryScore(shot int) int {
+	return ufoMysteryScores[shot%len(ufoMysteryScores)]
+}
+
+// canSpawnUFO checks the rules for sending a UFO: one at a time, not between
+// waves, only once the wave is under way, not when the fleet is nearly gone,
+// and never before the wait after the last one is over.
+func (g *Game) canSpawnUFO() bool {
+	switch {
+	case g.UFO != nil, g.ufoTimer != nil:
+		return false
+	case len(g.Aliens) == 0, g.waveTimer.IsRunning():
+		return false
+	case ALIENS_PER_WAVE-len(g.Aliens) < ufoFirstKills:
+		return false
+	case len(g.Aliens) < ufoMinAliens:
+		return false
+	}
+	return true
+}
+
+// pickUFOKind chooses a variant among those allowed in the current wave.
+func (g *Game) pickUFOKind() UFOKind {
+	total := 0
+	for _, kind := range ufoKinds {
+		if variant := ufoVariants[kind]; g.Wave >= variant.MinWave {
+			total += variant.Weight
+		}
+	}
+
+	roll := g.rng.Intn(total)
+	for _, kind := range ufoKinds {
+		variant := ufoVariants[kind]
+		if g.Wave < variant.MinWave {
+			continue
+		}
+		if roll < variant.Weight {
+			return kind
+		}
+		roll -= variant.Weight
+	}
+	return UFONormal
+}
+
+func (g *Game) SpawnUFO() {
+	if g.UFO == nil {
+		direction := -1
+		if g.rng.Intn(2) == 0 {
+			direction = 1
+		}
+		g.UFO = NewUFO(g.pickUFOKind(), direction, g.Wave)
+
+		// Start playing UFO sound at 50% volume, looping
+		ufoSound, err := g.sounds.Loop(SoundUFO, 0.5)
+		if err != nil {
+			log.Printf("Error creating UFO audio player: %v", err)
+		} else {
+			g.ufoSound = ufoSound
+		}
+	}
+}
+
+func (g *Game) UpdateUFO() {
+	if g.UFO == nil {
+		return
+	}
+
+	g.UFO.X += g.UFO.Speed * g.UFO.Direction
+	if g.UFO.HitFlash > 0 {
+		g.UFO.HitFlash--
+	}
+
+	// Bombers drop a shot whenever their bomb timer runs out while on screen
+	if g.UFO.BombTimer > 0 {
+		if g.UFO.BombTimer--; g.UFO.BombTimer == 0 {
+			g.dropUFOBomb()
+			g.UFO.BombTimer = ufoVariants[g.UFO.Kind].BombTicks
+		}
+	}
+
+	// Pan the warble to follow the UFO across the screen
+	if g.ufoSound != nil {
+		g.ufoSound.SetPan(float64(g.UFO.Center()))
+	}
+
+	// Remove UFO once it has crossed the screen
+	if g.UFO.OffScreen() {
+		g.removeUFO()
+	}
+}
+
+// dropUFOBomb fires an alien shot from under the UFO, if it's over the play area.
+func (g *Game) dropUFOBomb() {
+	shotWidth := assets.AlienShot.Bounds().Dx()
+	x := g.UFO.Center() - shotWidth/2
+	if x < 0 || x+shotWidth > Width {
+		return
+	}
+	g.AlienMissiles = append(g.AlienMissiles, &AlienMissile{
+		Sprite: assets.AlienShot,
+		X:      x,
+		Y:      g.UFO.Y + g.UFO.Sprite.Bounds().Dy(),
+	})
+}
+
+// hitUFO applies a player shot to the UFO, destroying it and scoring once its
+// armor is gone. It reports whether the UFO was destroyed.
+func (g *Game) hitUFO(missile *PlayerMissile) bool {
+	ufoRect := image.Rect(g.UFO.X, g.UFO.Y,
+		g.UFO.X+g.UFO.Sprite.Bounds().Dx(),
+		g.UFO.Y+g.UFO.Sprite.Bounds().Dy())
+
+	if g.UFO.HP--; g.UFO.HP > 0 {
+		// Armor took the hit
+		g.UFO.HitFlash = ufoHitFlashTicks
+		g.sounds.PlayPCM(synthUFOClank(g.sounds.SampleRate()))
+		return false
+	}
+
+	// The mystery score depends on how many shots the player had fired
+	points := ufoMysteryScore(missile.Shot) * ufoVariants[g.UFO.Kind].Multiplier * g.Combo.Multiplier()
+	g.Player.Points += points
+	g.UFOScore = &UFOScorePopup{X: g.UFO.Center(), Y: g.UFO.Y, Points: points, Ticks: UFOScorePopupTicks}
+
+	// Play alien explosion sound
+	g.sounds.PlayAt(SoundAlienExplosion, rectCenterX(ufoRect))
+
+	g.removeUFO()
+	return true
+}
+
+// removeUFO takes the UFO off screen, stops its warble and starts the wait for the next one.
+func (g *Game) removeUFO() {
+	g.UFO = nil
+	if g.ufoSound != nil {
+		g.sounds.StopLoop(g.ufoSound)
+		g.ufoSound = nil
+	}
+	g.StartUFOTimer()
+}
+
+func (g *Game) StartUFOTimer() {
+	// Random duration between 10-30 seconds
+	duration := time.Duration(ufoMinDelay+g.rng.Intn(ufoDelayVariation+1)) * time.Second
+	g.ufoTimer = NewTimer(duration)
+	g.ufoTimer.Start()
+}
+
+// synthUFOClank renders the ping of a shot glancing off an armored UFO as
+// 16-bit stereo PCM.
+func synthUFOClank(sampleRate int) []byte {
+	samples := int(0.08 * float64(sampleRate))
+
+	pcm := make([]byte, samples*4)
+	for i := 0; i < samples; i++ {
+		t := float64(i) / float64(sampleRate)
+
+		// Two detuned squares beat against each other like struck metal
+		value := 0.0
+		for _, freq := range []float64{1200, 1710} {
+			if math.Sin(2*math.Pi*freq*t) < 0 {
+				value -= 0.5
+			} else {
+				value += 0.5
+			}
+		}
+		envelope := math.Exp(-8 * float64(i) / float64(samples))
+		sample := int16(value * envelope * 0.2 * math.MaxInt16)
+
+		PutStereoSample(pcm, i, sample)
+	}
+	return pcm
+}
@@ -0,0 +1,62 @@
+package game
+
+import "testing"
+
+func TestUFOCrossingSpeed(t *testing.T) {
+	tests := []struct {
+		name      string
+		wave      int
+		direction int
+		wantStep  int
+	}{
+		{"wave 1 from the left", 1, 1, 1},
+		{"wave 1 from the right", 1, -1, -1},
+		{"faster wave", 1 + ufoWavesPerSpeed, 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(1, Options{}, silence{})
+			g.UFO = NewUFO(UFONormal, tt.direction, tt.wave)
+
+			start := g.UFO.X
+			for range 10 {
+				g.UpdateUFO()
+			}
+			if moved := g.UFO.X - start; moved != 10*tt.wantStep {
+				t.Errorf("UFO moved %d pixels in 10 ticks, want %d", moved, 10*tt.wantStep)
+			}
+		})
+	}
+}
+
+func TestArmoredUFOHits(t *testing.T) {
+	g := New(1, Options{}, silence{})
+	g.Aliens = nil
+	g.UFO = NewUFO(UFOArmored, 1, 3)
+	ufo := g.UFO
+	hits := ufo.HP
+
+	for hit := 1; hit <= hits; hit++ {
+		bounds := ufo.Sprite.Bounds()
+		missile := NewPlayerMissile(g.Player)
+		missile.X = ufo.X + bounds.Dx()/2 - missile.Sprite.Bounds().Dx()/2
+		missile.Y = ufo.Y + bounds.Dy()/2 - missile.Sprite.Bounds().Dy()/2
+		g.Player.Missiles = []*PlayerMissile{missile}
+
+		g.CheckPlayerMissileCollision()
+		if len(g.Player.Missiles) != 0 {
+			t.Fatalf("hit %d missed the UFO", hit)
+		}
+
+		wantCounted := 0
+		if g.UFO == nil {
+			wantCounted = 1
+		}
+		if g.Combo.Chain != wantCounted || g.waveHits != wantCounted {
+			t.Errorf("after hit %d: combo chain %d and wave hits %d, want %d", hit, g.Combo.Chain, g.waveHits, wantCounted)
+		}
+	}
+	if g.UFO != nil {
+		t.Errorf("UFO survived %d hits", hits)
+	}
+}
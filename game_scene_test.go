@@ -0,0 +1,91 @@
+package main
+
+import (
+	"invaders/assets"
+	"invaders/game"
+	"log"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	if err := assets.Load(); err != nil {
+		log.Fatalf("Error loading assets: %v", err)
+	}
+	os.Exit(m.Run())
+}
+
+// newRecordedGameScene starts a game whose sounds and music go to a RecordingAudio.
+func newRecordedGameScene() (*GameScene, *RecordingAudio) {
+	recorder := NewRecordingAudio()
+	sm := &SceneManager{settings: NewSettings(), sounds: recorder, music: recorder}
+	return newSeededGameScene(sm, 1), recorder
+}
+
+// stepUntil steps a scene with no input until done reports true, failing after maxTicks.
+func stepUntil(t *testing.T, g *GameScene, maxTicks int, done func() bool) {
+	t.Helper()
+
+	for range maxTicks {
+		if err := g.step(0); err != nil {
+			t.Fatal(err)
+		}
+		if done() {
+			return
+		}
+	}
+	t.Fatalf("still waiting after %d ticks", maxTicks)
+}
+
+func TestWaveClearSound(t *testing.T) {
+	g, recorder := newRecordedGameScene()
+	g.game.Aliens = nil
+
+	wave := g.game.Wave
+	stepUntil(t, g, 10*game.TicksPerSecond, func() bool { return g.game.Wave > wave })
+	if count := recorder.Count(EventPlay, game.SoundWaveClear); count != 1 {
+		t.Errorf("wave clear played %d times, want once", count)
+	}
+}
+
+func TestBaseHitSound(t *testing.T) {
+	g, recorder := newRecordedGameScene()
+	g.game.Aliens = g.game.Aliens[:1] // Keep the fleet from shooting too
+
+	base := g.game.Bases[0]
+	shotWidth := assets.AlienShot.Bounds().Dx()
+	g.game.AlienMissiles = append(g.game.AlienMissiles, &game.AlienMissile{
+		Sprite: assets.AlienShot,
+		X:      base.X + base.Width/2 - shotWidth/2,
+		Y:      base.Y - assets.AlienShot.Bounds().Dy(),
+	})
+
+	stepUntil(t, g, game.TicksPerSecond, func() bool {
+		return recorder.Count(EventPlay, game.SoundAlienExplosion) > 0
+	})
+	for _, event := range recorder.Events {
+		if event.Kind == EventPlay && event.Sound == game.SoundAlienExplosion {
+			if event.X < float64(base.X) || event.X > float64(base.X+base.Width) {
+				t.Errorf("base hit played at x %.0f, want within the base at %d-%d", event.X, base.X, base.X+base.Width)
+			}
+		}
+	}
+}
+
+func TestUFOLoop(t *testing.T) {
+	g, recorder := newRecordedGameScene()
+	g.game.Aliens = g.game.Aliens[:1]
+
+	g.game.SpawnUFO()
+	if !recorder.Looping(game.SoundUFO) {
+		t.Fatal("UFO spawned without its loop")
+	}
+
+	stepUntil(t, g, 30*game.TicksPerSecond, func() bool { return g.game.UFO == nil })
+	if recorder.Looping(game.SoundUFO) {
+		t.Error("UFO loop still playing after the UFO left")
+	}
+	if starts, stops := recorder.Count(EventLoopStart, game.SoundUFO), recorder.Count(EventLoopStop, game.SoundUFO); starts != stops {
+		t.Errorf("UFO loop started %d times and stopped %d times", starts, stops)
+	}
+}
@@ -3,7 +3,6 @@ module invaders
 go 1.24.3
 
 require (
-	github.com/RAshkettle/Stopwatch v1.0.3
 	github.com/hajimehoshi/ebiten/v2 v2.8.8
 	golang.org/x/image v0.20.0
 )
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+)
+
+const (
+	highScoresFileName = "highscores.json"
+	maxHighScores      = 10
+	initialsLength     = 3
+)
+
+// defaultDifficulty is recorded with every score until the game has a difficulty setting.
+const defaultDifficulty = "Normal"
+
+type HighScore struct {
+	Score      int       `json:"score"`
+	Initials   string    `json:"initials"`
+	Wave       int       `json:"wave"`
+	Difficulty string    `json:"difficulty"`
+	Date       time.Time `json:"date"`
+}
+
+// HighScoreTable is the top scores, best first.
+type HighScoreTable struct {
+	Entries []HighScore `json:"entries"`
+}
+
+// userDataDir returns the per-user directory for application data, following
+// the platform convention the way os.UserConfigDir does for settings.
+func userDataDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("LocalAppData"); dir != "" {
+			return dir, nil
+		}
+		return "", errors.New("%LocalAppData% is not defined")
+	case "darwin", "ios":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Application Support"), nil
+	default:
+		if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+			return dir, nil
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".local", "share"), nil
+	}
+}
+
+// highScoresPath returns where the table is persisted in the user data directory.
+func highScoresPath() (string, error) {
+	dataDir, err := userDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "invaders", highScoresFileName), nil
+}
+
+// LoadHighScores reads the saved table, starting an empty one if none has been saved yet.
+func LoadHighScores() (*HighScoreTable, error) {
+	table := &HighScoreTable{}
+
+	path, err := highScoresPath()
+	if err != nil {
+		return table, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return table, nil
+	}
+	if err != nil {
+		return table, err
+	}
+
+	if err := json.Unmarshal(data, table); err != nil {
+		return &HighScoreTable{}, err
+	}
+	table.sort()
+	return table, nil
+}
+
+func (t *HighScoreTable) Save() error {
+	path, err := highScoresPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Qualifies reports whether a score would make it onto the table.
+func (t *HighScoreTable) Qualifies(score int) bool {
+	if score <= 0 {
+		return false
+	}
+	return len(t.Entries) < maxHighScores || score > t.Entries[len(t.Entries)-1].Score
+}
+
+// Add inserts a score and returns its rank from 0, or -1 if it didn't qualify.
+// Ties rank below the scores already on the table.
+func (t *HighScoreTable) Add(entry HighScore) int {
+	if !t.Qualifies(entry.Score) {
+		return -1
+	}
+
+	rank := sort.Search(len(t.Entries), func(i int) bool {
+		return t.Entries[i].Score < entry.Score
+	})
+	t.Entries = append(t.Entries, HighScore{})
+	copy(t.Entries[rank+1:], t.Entries[rank:])
+	t.Entries[rank] = entry
+
+	if len(t.Entries) > maxHighScores {
+		t.Entries = t.Entries[:maxHighScores]
+	}
+	return rank
+}
+
+// Best returns the top score, or 0 for an empty table.
+func (t *HighScoreTable) Best() int {
+	if len(t.Entries) == 0 {
+		return 0
+	}
+	return t.Entries[0].Score
+}
+
+func (t *HighScoreTable) sort() {
+	sort.SliceStable(t.Entries, func(i, j int) bool {
+		return t.Entries[i].Score > t.Entries[j].Score
+	})
+	if len(t.Entries) > maxHighScores {
+		t.Entries = t.Entries[:maxHighScores]
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"invaders/assets"
+	"invaders/game"
+	"invaders/textures"
+	"log"
+	"os"
+	"path"
+	"strings"
+)
+
+const hotReloadInterval = 30 // Ticks between polls of the asset directory
+
+// HotReloader watches a content pack directory in -dev mode and swaps changed
+// sprites and sounds into the running game.
+type HotReloader struct {
+	watcher *assets.Watcher
+	sounds  *SoundBank
+	ticks   int
+}
+
+func NewHotReloader(dir string, sounds *SoundBank) (*HotReloader, error) {
+	watcher, err := assets.NewWatcher(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+	return &HotReloader{watcher: watcher, sounds: sounds}, nil
+}
+
+func (h *HotReloader) Update() {
+	h.ticks++
+	if h.ticks%hotReloadInterval != 0 {
+		return
+	}
+
+	changed, err := h.watcher.Poll()
+	if err != nil {
+		log.Printf("Error watching assets: %v", err)
+		return
+	}
+
+	soundsChanged := false
+	for _, filePath := range changed {
+		ext := path.Ext(filePath)
+		if ext != ".png" && ext != ".json" && ext != ".ogg" {
+			continue
+		}
+		if err := assets.Reload(filePath); err != nil {
+			log.Printf("Error reloading asset: %v", err)
+			continue
+		}
+		if ext == ".png" || ext == ".json" {
+			imagePath := filePath
+			if ext == ".json" {
+				imagePath = strings.TrimSuffix(filePath, ext) + ".png" // A sidecar re-cuts the sheet it sits beside
+			}
+			if err := textures.Reload(imagePath); err != nil {
+				log.Printf("Error uploading asset: %v", err)
+				continue
+			}
+			game.ReloadMaterial(imagePath)
+		}
+		log.Printf("Reloaded %s", filePath)
+		soundsChanged = soundsChanged || ext == ".ogg"
+	}
+
+	if soundsChanged {
+		if err := h.sounds.Reload(); err != nil {
+			log.Printf("Error reloading sounds: %v", err)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"invaders/game"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// readPlayerInput reads this tick's input from the keyboard.
+func readPlayerInput() game.PlayerInput {
+	var input game.PlayerInput
+	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
+		input |= game.InputLeft
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
+		input |= game.InputRight
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		input |= game.InputFire
+	}
+	return input
+}
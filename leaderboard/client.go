@@ -0,0 +1,246 @@
+package leaderboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	requestTimeout = 10 * time.Second
+	minRetryDelay  = time.Second
+	maxRetryDelay  = 2 * time.Minute
+)
+
+// errRejected marks a submission the server refused as invalid, which is never retried.
+var errRejected = errors.New("rejected by server")
+
+// Client talks to a leaderboard server from a background goroutine, so
+// nothing it does blocks the game. Submissions that fail are queued and
+// retried with a growing delay until they get through.
+type Client struct {
+	baseURL string
+	http    *http.Client
+
+	mu      sync.Mutex
+	pending []Score // Submissions waiting to be sent, oldest first
+	top     []Score // Latest scores fetched from the server
+	lastErr error
+
+	wake chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewClient starts a client for the server at baseURL, e.g. http://localhost:8080.
+func NewClient(baseURL string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("leaderboard URL %q must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: requestTimeout},
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	c.wg.Add(1)
+	go c.run()
+	return c, nil
+}
+
+// Submit queues a score to be sent. It never blocks.
+func (c *Client) Submit(score Score) {
+	c.mu.Lock()
+	c.pending = append(c.pending, score)
+	c.mu.Unlock()
+	c.poke()
+}
+
+// Refresh asks for the top scores to be fetched again. It never blocks; the
+// result shows up in Top.
+func (c *Client) Refresh() {
+	c.mu.Lock()
+	c.top = nil
+	c.mu.Unlock()
+	c.poke()
+}
+
+// Top returns the latest top scores fetched from the server, or nil if none
+// have arrived since the last Refresh.
+func (c *Client) Top() []Score {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.top
+}
+
+// Pending returns how many submissions are still waiting to be sent.
+func (c *Client) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
+
+// Err returns the last error talking to the server, or nil once a request succeeds.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastErr
+}
+
+// Close stops the background goroutine. Unsent submissions are dropped.
+func (c *Client) Close() {
+	close(c.done)
+	c.wg.Wait()
+}
+
+func (c *Client) poke() {
+	select {
+	case c.wake <- struct{}{}:
+	default: // Already awake
+	}
+}
+
+func (c *Client) run() {
+	defer c.wg.Done()
+
+	delay := minRetryDelay
+	for {
+		var retry <-chan time.Time
+		if c.work() {
+			delay = minRetryDelay
+		} else {
+			retry = time.After(delay)
+			delay = min(delay*2, maxRetryDelay)
+		}
+
+		select {
+		case <-c.done:
+			return
+		case <-c.wake:
+		case <-retry:
+		}
+	}
+}
+
+// work sends queued submissions and fetches the top scores if they've been
+// asked for. It reports false if anything is left to retry.
+func (c *Client) work() bool {
+	for {
+		c.mu.Lock()
+		if len(c.pending) == 0 {
+			c.mu.Unlock()
+			break
+		}
+		score := c.pending[0]
+		c.mu.Unlock()
+
+		err := c.submit(score)
+		if err != nil && !errors.Is(err, errRejected) {
+			c.setErr(err)
+			return false
+		}
+		if err != nil {
+			log.Printf("Leaderboard dropped score %d: %v", score.Score, err)
+		}
+
+		c.mu.Lock()
+		c.pending = c.pending[1:]
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	wantTop := c.top == nil
+	c.mu.Unlock()
+	if wantTop {
+		top, err := c.fetchTop(defaultLimit)
+		if err != nil {
+			c.setErr(err)
+			return false
+		}
+		c.mu.Lock()
+		c.top = top
+		c.mu.Unlock()
+	}
+
+	c.setErr(nil)
+	return true
+}
+
+func (c *Client) setErr(err error) {
+	c.mu.Lock()
+	c.lastErr = err
+	c.mu.Unlock()
+}
+
+func (c *Client) submit(score Score) error {
+	body, err := json.Marshal(score)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+scoresPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusCreated:
+		return nil
+	case resp.StatusCode == http.StatusBadRequest:
+		var e errorResponse
+		json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("%w: %s", errRejected, e.Error)
+	default:
+		return fmt.Errorf("submitting score: %s", resp.Status)
+	}
+}
+
+func (c *Client) fetchTop(limit int) ([]Score, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s?limit=%d", c.baseURL, scoresPath, limit), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching scores: %s", resp.Status)
+	}
+
+	var scores scoresResponse
+	if err := json.NewDecoder(resp.Body).Decode(&scores); err != nil {
+		return nil, err
+	}
+	if scores.Scores == nil {
+		scores.Scores = []Score{} // Fetched, just empty
+	}
+	return scores.Scores, nil
+}
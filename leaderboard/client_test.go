@@ -0,0 +1,87 @@
+package leaderboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer serves s, or drops every connection while down is set, as a
+// server that's offline does.
+type flakyServer struct {
+	*Server
+	down atomic.Bool
+}
+
+func (f *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.down.Load() {
+		panic(http.ErrAbortHandler)
+	}
+	f.Server.ServeHTTP(w, r)
+}
+
+// waitFor polls until done reports true, failing if it takes longer than the client's retries should.
+func waitFor(t *testing.T, what string, done func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newTestClient(t *testing.T, url string) *Client {
+	t.Helper()
+
+	c, err := NewClient(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestClientQueuesWhileOffline(t *testing.T) {
+	server := &flakyServer{Server: newTestServer(t)}
+	server.down.Store(true)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	c := newTestClient(t, ts.URL)
+	c.Submit(replayedScore(100, 1))
+	waitFor(t, "the failed submission", func() bool { return c.Err() != nil })
+	if c.Pending() != 1 {
+		t.Fatalf("%d submissions pending while offline, want 1", c.Pending())
+	}
+
+	server.down.Store(false)
+	waitFor(t, "the retried submission", func() bool { return c.Pending() == 0 && c.Top() != nil })
+	if c.Err() != nil {
+		t.Errorf("Err = %v after the retry went through", c.Err())
+	}
+	if top := c.Top(); len(top) != 1 || top[0].Score != 100 {
+		t.Errorf("Top = %+v, want the queued score", top)
+	}
+}
+
+func TestClientDropsRejectedScores(t *testing.T) {
+	s := newTestServer(t)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	c := newTestClient(t, ts.URL)
+	invalid := replayedScore(100, 1)
+	invalid.Wave = 0
+	c.Submit(invalid)
+	c.Submit(replayedScore(200, 2))
+
+	waitFor(t, "both submissions", func() bool { return c.Pending() == 0 && c.Top() != nil })
+	if top := c.Top(); len(top) != 1 || top[0].Score != 200 {
+		t.Errorf("Top = %+v, want only the valid score", top)
+	}
+}
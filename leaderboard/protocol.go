@@ -0,0 +1,83 @@
+// Package leaderboard submits scores to, and fetches them from, an online
+// leaderboard over a small HTTP/JSON protocol. It also has the reference
+// server, which keeps the best scores in a local JSON file.
+//
+// The protocol has two requests:
+//
+//	POST /scores
+//	{"initials": "ABC", "score": 1230, "wave": 3, "difficulty": "Normal", "date": "2026-10-19T12:00:00Z"}
+//
+// adds a score. Scores also carry "seed" and "replay", the game's random seed
+// and recorded input, which a server may re-simulate to check the score. The
+// server answers 201 Created with {"rank": 4}, the score's position from 1,
+// or 400 Bad Request with {"error": "..."} if the score is invalid or fails
+// verification. Clients retry other failures later; a 400 is never retried.
+// A game submitted twice keeps the rank it was first given.
+//
+//	GET /scores?limit=10
+//
+// answers 200 OK with {"scores": [...]}, the best scores first, without their
+// replays. limit defaults to 10 and is capped at 100.
+package leaderboard
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	scoresPath   = "/scores"
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+// Score is one leaderboard entry.
+type Score struct {
+	Initials   string    `json:"initials"`
+	Score      int       `json:"score"`
+	Wave       int       `json:"wave"`
+	Difficulty string    `json:"difficulty"`
+	Date       time.Time `json:"date"`
+
+	// The game's random seed and recorded input, so a server can re-simulate
+	// the game to check the score. The replay format belongs to the game.
+	Seed   int64           `json:"seed"`
+	Replay json.RawMessage `json:"replay,omitempty"`
+}
+
+type submitResponse struct {
+	Rank int `json:"rank"`
+}
+
+type scoresResponse struct {
+	Scores []Score `json:"scores"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// initialsChars are the characters allowed in initials, as on the game's entry screen.
+const initialsChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 "
+
+func (s Score) Validate() error {
+	var errs []error
+	if len(s.Initials) == 0 || len(s.Initials) > 3 {
+		errs = append(errs, errors.New("initials must be 1 to 3 characters"))
+	}
+	for _, c := range s.Initials {
+		if !strings.ContainsRune(initialsChars, c) {
+			errs = append(errs, errors.New("initials may only use A-Z, 0-9 and space"))
+			break
+		}
+	}
+	if s.Score < 0 {
+		errs = append(errs, errors.New("score must not be negative"))
+	}
+	if s.Wave < 1 {
+		errs = append(errs, errors.New("wave must be at least 1"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,235 @@
+package leaderboard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxSubmissionSize leaves room for the replay of a long game.
+	maxSubmissionSize = 8 << 20
+
+	// defaultKeep is how many of the best scores a server keeps. Lower
+	// scores are ranked but not stored.
+	defaultKeep = 1000
+)
+
+// Server is the reference leaderboard server. It keeps the best scores in
+// memory and in a JSON file, and the replay of each in a file of its own in
+// a directory beside it, so adding a score never rewrites the replays.
+type Server struct {
+	// Verify, if set, checks each submission, typically by re-simulating its
+	// replay. Submissions it returns an error for are rejected.
+	Verify func(Score) error
+
+	path      string
+	replayDir string
+	keep      int // Most scores kept
+
+	mu     sync.Mutex
+	scores []storedScore // Best first
+}
+
+// storedScore is a kept score. Its replay is in its own file, named by ID.
+type storedScore struct {
+	Score
+	ID string `json:"id"` // Identifies the game the score came from
+}
+
+// NewServer loads the scores in path, starting empty if the file doesn't
+// exist yet. Replays are kept in path.replays.
+func NewServer(path string) (*Server, error) {
+	s := &Server{path: path, replayDir: path + ".replays", keep: defaultKeep}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.scores); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	sortScores(s.scores)
+	s.scores = s.scores[:min(len(s.scores), s.keep)]
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != scoresPath {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.handleTop(w, r)
+	case http.MethodPost:
+		s.handleSubmit(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+	}
+}
+
+func (s *Server) handleTop(w http.ResponseWriter, r *http.Request) {
+	limit := defaultLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "limit must be a positive number"})
+			return
+		}
+		limit = min(n, maxLimit)
+	}
+
+	// Replays are kept for audits but are too big to send with every listing,
+	// so they were never loaded
+	s.mu.Lock()
+	top := make([]Score, min(limit, len(s.scores)))
+	for i := range top {
+		top[i] = s.scores[i].Score
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, scoresResponse{Scores: top})
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var score Score
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSubmissionSize))
+	if err := decoder.Decode(&score); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	if err := score.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	if s.Verify != nil {
+		if err := s.Verify(score); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "verification failed: " + err.Error()})
+			return
+		}
+	}
+
+	rank, err := s.add(score)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusCreated, submitResponse{Rank: rank})
+}
+
+// add stores a score and returns its rank from 1. Ties rank below existing
+// scores. A game submitted again, such as by a client retrying after its
+// connection dropped, keeps the rank it already has. Scores below the ones
+// kept get the rank they would have had, but aren't stored.
+func (s *Server) add(score Score) (int, error) {
+	id := gameID(score)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, stored := range s.scores {
+		if stored.ID == id {
+			return i + 1, nil
+		}
+	}
+
+	index := sort.Search(len(s.scores), func(i int) bool {
+		return s.scores[i].Score.Score < score.Score
+	})
+	if index >= s.keep {
+		return index + 1, nil
+	}
+
+	if err := s.writeReplay(id, score.Replay); err != nil {
+		return 0, err
+	}
+	score.Replay = nil
+
+	s.scores = append(s.scores, storedScore{})
+	copy(s.scores[index+1:], s.scores[index:])
+	s.scores[index] = storedScore{Score: score, ID: id}
+
+	// Scores pushed off the end take their replays with them
+	if len(s.scores) > s.keep {
+		for _, dropped := range s.scores[s.keep:] {
+			if err := os.Remove(s.replayPath(dropped.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Printf("Error removing replay: %v", err)
+			}
+		}
+		s.scores = s.scores[:s.keep]
+	}
+
+	return index + 1, s.save()
+}
+
+// gameID identifies the game a score came from: its seed and replay, which
+// together decide the score. Scores without a replay are identified by all
+// of their fields.
+func gameID(score Score) string {
+	hash := sha256.New()
+	if len(score.Replay) == 0 {
+		fmt.Fprintf(hash, "%q %d %d %q %s\n", score.Initials, score.Score, score.Wave, score.Difficulty, score.Date.Format(time.RFC3339Nano))
+	}
+	fmt.Fprintf(hash, "%d\n", score.Seed)
+	hash.Write(score.Replay)
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+func (s *Server) replayPath(id string) string {
+	return filepath.Join(s.replayDir, id+".json")
+}
+
+// writeReplay stores a replay in its own file. Scores without one store nothing.
+func (s *Server) writeReplay(id string, replay json.RawMessage) error {
+	if len(replay) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(s.replayDir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.replayPath(id), replay, 0o644)
+}
+
+// save writes the scores to a temporary file and renames it over the old
+// one, so a crash never leaves a half-written leaderboard.
+func (s *Server) save() error {
+	data, err := json.MarshalIndent(s.scores, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func sortScores(scores []storedScore) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score.Score > scores[j].Score.Score
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
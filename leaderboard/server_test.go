@@ -0,0 +1,228 @@
+package leaderboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newTestServer starts a server on an empty scores file in a temporary directory.
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	s, err := NewServer(filepath.Join(t.TempDir(), "scores.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// replayedScore is a score with a replay, as the game submits.
+func replayedScore(points int, seed int64) Score {
+	return Score{Initials: "AAA", Score: points, Wave: 1, Seed: seed, Replay: json.RawMessage(`{"inputs":[[0,60]]}`)}
+}
+
+func TestServerKeepsReplaysApart(t *testing.T) {
+	s := newTestServer(t)
+	if _, err := s.add(replayedScore(100, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored []storedScore
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || stored[0].Replay != nil {
+		t.Fatalf("scores file = %s, want one score without its replay", data)
+	}
+
+	replay, err := os.ReadFile(s.replayPath(stored[0].ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(replay) != `{"inputs":[[0,60]]}` {
+		t.Errorf("replay file = %s", replay)
+	}
+}
+
+func TestServerDedupesResubmissions(t *testing.T) {
+	s := newTestServer(t)
+	for _, points := range []int{300, 100} {
+		if _, err := s.add(replayedScore(points, int64(points))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The same game again, under other initials
+	again := replayedScore(100, 100)
+	again.Initials = "BBB"
+	rank, err := s.add(again)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rank != 2 || len(s.scores) != 2 {
+		t.Errorf("resubmission got rank %d with %d scores kept, want rank 2 of 2", rank, len(s.scores))
+	}
+}
+
+func TestServerCapsScores(t *testing.T) {
+	s := newTestServer(t)
+	s.keep = 2
+
+	var dropped string
+	for i, points := range []int{100, 300, 200} {
+		if _, err := s.add(replayedScore(points, int64(i))); err != nil {
+			t.Fatal(err)
+		}
+		if points == 100 {
+			dropped = s.replayPath(s.scores[0].ID)
+		}
+	}
+	if len(s.scores) != 2 || s.scores[0].Score.Score != 300 || s.scores[1].Score.Score != 200 {
+		t.Fatalf("kept %+v, want 300 and 200", s.scores)
+	}
+	if _, err := os.Stat(dropped); !os.IsNotExist(err) {
+		t.Errorf("replay of the dropped score is still there: %v", err)
+	}
+
+	// Too low to keep, but still ranked
+	rank, err := s.add(replayedScore(50, 9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rank != 3 || len(s.scores) != 2 {
+		t.Errorf("low score got rank %d with %d scores kept, want rank 3 of 2", rank, len(s.scores))
+	}
+
+	reloaded, err := NewServer(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.scores) != 2 || reloaded.scores[0].ID != s.scores[0].ID {
+		t.Errorf("reloaded %+v, want %+v", reloaded.scores, s.scores)
+	}
+}
+
+// post submits a body to the server and decodes the answer into response.
+func post(t *testing.T, url string, body []byte, response any) int {
+	t.Helper()
+
+	resp, err := http.Post(url+scoresPath, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+func TestServerSubmitAndList(t *testing.T) {
+	ts := httptest.NewServer(newTestServer(t))
+	defer ts.Close()
+
+	for i, tt := range []struct {
+		points   int
+		wantRank int
+	}{
+		{100, 1},
+		{300, 1},
+		{200, 2},
+		{200, 3}, // Ties rank below
+	} {
+		body, err := json.Marshal(replayedScore(tt.points, int64(i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var answer submitResponse
+		if status := post(t, ts.URL, body, &answer); status != http.StatusCreated {
+			t.Fatalf("submitting %d: status %d", tt.points, status)
+		}
+		if answer.Rank != tt.wantRank {
+			t.Errorf("submitting %d: rank %d, want %d", tt.points, answer.Rank, tt.wantRank)
+		}
+	}
+
+	resp, err := http.Get(ts.URL + scoresPath + "?limit=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var listing scoresResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		t.Fatal(err)
+	}
+
+	var points []int
+	for _, score := range listing.Scores {
+		points = append(points, score.Score)
+		if score.Replay != nil {
+			t.Errorf("listing of %d includes its replay", score.Score)
+		}
+	}
+	if !slices.Equal(points, []int{300, 200, 200}) {
+		t.Errorf("listing = %v, want [300 200 200]", points)
+	}
+}
+
+func TestServerRejects(t *testing.T) {
+	s := newTestServer(t)
+	s.Verify = func(score Score) error {
+		if score.Seed == 666 {
+			return errors.New("replay scores 0")
+		}
+		return nil
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	marshal := func(score Score) []byte {
+		data, err := json.Marshal(score)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	oversized := replayedScore(100, 1)
+	oversized.Replay = json.RawMessage(`"` + strings.Repeat("x", maxSubmissionSize) + `"`)
+	badInitials := replayedScore(100, 1)
+	badInitials.Initials = "a!"
+
+	tests := []struct {
+		name    string
+		body    []byte
+		wantErr string
+	}{
+		{"not JSON", []byte("{"), "unexpected EOF"},
+		{"invalid score", marshal(badInitials), "initials"},
+		{"oversized", marshal(oversized), "too large"},
+		{"failed verification", marshal(replayedScore(100, 666)), "verification failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var answer errorResponse
+			if status := post(t, ts.URL, tt.body, &answer); status != http.StatusBadRequest {
+				t.Errorf("status %d, want %d", status, http.StatusBadRequest)
+			}
+			if !strings.Contains(answer.Error, tt.wantErr) {
+				t.Errorf("error %q, want it to mention %q", answer.Error, tt.wantErr)
+			}
+		})
+	}
+
+	if len(s.scores) != 0 {
+		t.Errorf("rejected submissions were stored: %+v", s.scores)
+	}
+}
@@ -1,16 +1,130 @@
 package main
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"flag"
+	"invaders/assets"
+	"invaders/game"
+	"invaders/leaderboard"
+	"invaders/textures"
+	"log"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
 
 func main() {
+	ripple := flag.Bool("ripple", false, "march aliens one at a time like the arcade hardware")
+	sfxConfig := flag.String("sfx", "", "JSON file of sfxr parameters to synthesize sound effects from")
+	pack := flag.String("pack", "", "content pack directory or .zip whose files override the built-in assets")
+	dev := flag.Bool("dev", false, "reload sprites and sounds from the -pack directory as they change")
+	noAtlas := flag.Bool("no-atlas", false, "load each sprite into its own texture instead of one atlas")
+	dumpAtlas := flag.String("dump-atlas", "", "write the sprite atlas to this PNG file")
+	drawStats := flag.Bool("draw-stats", false, "log sprite draws and batches per frame")
+	leaderboardURL := flag.String("leaderboard", "", "URL of an online leaderboard server to submit scores to")
+	flag.Parse()
+
+	if *pack != "" {
+		manifest, err := assets.UsePack(*pack)
+		if err != nil {
+			log.Fatalf("Error loading content pack:\n%v", err)
+		}
+		log.Printf("Using content pack %q %s", manifest.Name, manifest.Version)
+	}
+
+	if err := assets.Load(); err != nil {
+		if !assets.DevBuild {
+			log.Fatalf("Error loading assets:\n%v", err)
+		}
+		log.Printf("Using placeholders for assets that failed to load:\n%v", err)
+	}
+
+	textures.UseAtlas = !*noAtlas
+	if err := textures.Load(); err != nil {
+		if !assets.DevBuild {
+			log.Fatalf("Error loading textures:\n%v", err)
+		}
+		log.Printf("Some textures failed to load:\n%v", err)
+	}
+
+	if *dumpAtlas != "" {
+		if err := writeAtlas(*dumpAtlas); err != nil {
+			log.Fatalf("Error writing atlas: %v", err)
+		}
+	}
+
+	settings, err := LoadSettings()
+	if err != nil {
+		log.Printf("Error loading settings, using defaults: %v", err)
+	}
+	if *ripple {
+		settings.MarchMode = game.MarchRipple
+	}
+
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetWindowTitle("Invaders")
 	ebiten.SetWindowSize(640, 480)
 
-	sceneManager := NewSceneManager()
+	audioContext := audio.NewContext(44100)
+	sounds, err := NewSoundBank(audioContext, &settings.Mixer)
+	if err != nil {
+		if !assets.DevBuild {
+			log.Fatalf("Error loading sounds: %v", err)
+		}
+		log.Printf("Some sounds failed to decode and will be silent:\n%v", err)
+	}
+	if *sfxConfig != "" {
+		if err := sounds.LoadSynthConfig(*sfxConfig); err != nil {
+			log.Fatalf("Error synthesizing sounds: %v", err)
+		}
+	}
+
+	music := NewMusicPlayer(audioContext, &settings.Mixer)
+
+	highScores, err := LoadHighScores()
+	if err != nil {
+		log.Printf("Error loading high scores, starting a new table: %v", err)
+	}
+
+	sceneManager := NewSceneManager(settings, highScores, sounds, music)
+	if *drawStats {
+		sceneManager.drawStats = &DrawStats{}
+	}
+
+	if *leaderboardURL != "" {
+		client, err := leaderboard.NewClient(*leaderboardURL)
+		if err != nil {
+			log.Fatalf("Error starting leaderboard client: %v", err)
+		}
+		defer client.Close()
+		sceneManager.leaderboard = client
+	}
+
+	if *dev {
+		if info, err := os.Stat(*pack); *pack == "" || err != nil || !info.IsDir() {
+			log.Fatal("-dev needs -pack to point at a directory to watch")
+		}
+		hotReload, err := NewHotReloader(*pack, sounds)
+		if err != nil {
+			log.Fatalf("Error watching %s: %v", *pack, err)
+		}
+		sceneManager.hotReload = hotReload
+	}
 
-	err := ebiten.RunGame(sceneManager)
+	err = ebiten.RunGame(sceneManager)
 	if err != nil {
 		panic(err)
 	}
 }
+
+func writeAtlas(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := textures.DumpAtlas(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
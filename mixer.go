@@ -0,0 +1,75 @@
+package main
+
+import "math"
+
+type Bus int
+
+const (
+	BusMaster Bus = iota
+	BusSFX
+	BusMusic
+)
+
+const mixerStep = 0.1 // Level change per key press on the options screen
+
+// BusLevel is the volume of one mixer bus, from 0 (silent) to 1 (full).
+type BusLevel struct {
+	Level float64 `json:"level"`
+	Muted bool    `json:"muted"`
+}
+
+// Mixer holds the master, sound effect and music buses. Effects and music
+// play at their own bus level scaled by the master bus.
+type Mixer struct {
+	Master BusLevel `json:"master"`
+	SFX    BusLevel `json:"sfx"`
+	Music  BusLevel `json:"music"`
+}
+
+func NewMixer() Mixer {
+	return Mixer{
+		Master: BusLevel{Level: 1},
+		SFX:    BusLevel{Level: 1},
+		Music:  BusLevel{Level: 0.7},
+	}
+}
+
+func (m *Mixer) Bus(bus Bus) *BusLevel {
+	switch bus {
+	case BusSFX:
+		return &m.SFX
+	case BusMusic:
+		return &m.Music
+	default:
+		return &m.Master
+	}
+}
+
+// Volume returns the effective volume of a bus after master and mutes are applied.
+func (m *Mixer) Volume(bus Bus) float64 {
+	if m.Master.Muted {
+		return 0
+	}
+	if bus == BusMaster {
+		return m.Master.Level
+	}
+
+	level := m.Bus(bus)
+	if level.Muted {
+		return 0
+	}
+	return m.Master.Level * level.Level
+}
+
+// Adjust nudges a bus level by delta, clamped to 0-1.
+func (m *Mixer) Adjust(bus Bus, delta float64) {
+	level := m.Bus(bus)
+	// Round to the step so repeated presses land on clean values
+	level.Level = math.Round((level.Level+delta)/mixerStep) * mixerStep
+	level.Level = math.Max(0, math.Min(1, level.Level))
+}
+
+func (m *Mixer) ToggleMute(bus Bus) {
+	level := m.Bus(bus)
+	level.Muted = !level.Muted
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"invaders/game"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+type MusicTrack int
+
+const (
+	MusicNone MusicTrack = iota
+	MusicTitle
+	MusicGame
+	MusicGameOver
+)
+
+const (
+	musicFadeFrames = 60 // One second crossfade at 60 TPS
+	musicGain       = 0.35
+)
+
+type waveform int
+
+const (
+	waveSquare waveform = iota
+	waveTriangle
+)
+
+// musicPattern is a looping sequence of MIDI notes, one per step. Zero is a rest.
+type musicPattern struct {
+	notes    []int
+	bpm      float64
+	stepBeat float64 // Length of a step in beats
+	wave     waveform
+}
+
+type musicTrackDef struct {
+	base  musicPattern
+	layer *musicPattern // Fades in with gameplay intensity, nil if the track has none
+}
+
+var musicTracks = map[MusicTrack]musicTrackDef{
+	MusicTitle: {
+		base: musicPattern{
+			notes:    []int{57, 60, 64, 60, 57, 60, 64, 67, 55, 59, 62, 59, 53, 57, 60, 64},
+			bpm:      90,
+			stepBeat: 0.5,
+			wave:     waveTriangle,
+		},
+	},
+	MusicGame: {
+		base: musicPattern{
+			notes:    []int{40, 0, 40, 0, 38, 0, 38, 0, 36, 0, 36, 0, 35, 0, 35, 0},
+			bpm:      120,
+			stepBeat: 0.5,
+			wave:     waveSquare,
+		},
+		layer: &musicPattern{
+			notes: []int{
+				64, 67, 71, 67, 62, 66, 69, 66, 60, 64, 67, 64, 59, 62, 66, 62,
+				64, 67, 71, 67, 62, 66, 69, 66, 60, 64, 67, 64, 59, 62, 66, 62,
+			},
+			bpm:      120,
+			stepBeat: 0.25,
+			wave:     waveSquare,
+		},
+	},
+	MusicGameOver: {
+		base: musicPattern{
+			notes:    []int{64, 0, 62, 0, 60, 0, 59, 0, 57, 0, 0, 0, 0, 0, 0, 0},
+			bpm:      70,
+			stepBeat: 0.5,
+			wave:     waveTriangle,
+		},
+	},
+}
+
+type musicVoice struct {
+	track MusicTrack
+	base  *audio.Player
+	layer *audio.Player
+	fade  float64 // 0-1 position in the crossfade
+}
+
+// MusicPlayer loops one track per scene and crossfades between them.
+type MusicPlayer struct {
+	context   *audio.Context
+	mixer     *Mixer
+	pcm       map[MusicTrack][2][]byte // Rendered base and layer for each track
+	current   *musicVoice
+	outgoing  []*musicVoice
+	intensity float64
+}
+
+// NewMusicPlayer renders every track up front so scene changes never stall.
+func NewMusicPlayer(context *audio.Context, mixer *Mixer) *MusicPlayer {
+	m := &MusicPlayer{
+		context: context,
+		mixer:   mixer,
+		pcm:     make(map[MusicTrack][2][]byte),
+	}
+
+	for track, def := range musicTracks {
+		rendered := [2][]byte{renderPattern(context.SampleRate(), def.base)}
+		if def.layer != nil {
+			rendered[1] = renderPattern(context.SampleRate(), *def.layer)
+		}
+		m.pcm[track] = rendered
+	}
+
+	return m
+}
+
+// PlayTrack crossfades to a track. Playing the current track again does nothing.
+func (m *MusicPlayer) PlayTrack(track MusicTrack) {
+	if m.current != nil && m.current.track == track {
+		return
+	}
+
+	if m.current != nil {
+		m.outgoing = append(m.outgoing, m.current)
+		m.current = nil
+	}
+
+	if track == MusicNone {
+		return
+	}
+
+	voice := &musicVoice{track: track}
+	rendered := m.pcm[track]
+	for i, pcm := range rendered {
+		if pcm == nil {
+			continue
+		}
+		player, err := m.context.NewPlayer(audio.NewInfiniteLoop(bytes.NewReader(pcm), int64(len(pcm))))
+		if err != nil {
+			log.Printf("Error creating music player: %v", err)
+			voice.stop()
+			return
+		}
+		if i == 0 {
+			voice.base = player
+		} else {
+			voice.layer = player
+		}
+	}
+
+	// Start the layer alongside the base so they stay in step
+	for _, player := range []*audio.Player{voice.base, voice.layer} {
+		if player != nil {
+			player.SetVolume(0)
+			player.Play()
+		}
+	}
+	m.current = voice
+	m.applyVolume(voice)
+}
+
+// SetIntensity sets how strongly the intensity layer plays, from 0 to 1.
+func (m *MusicPlayer) SetIntensity(intensity float64) {
+	m.intensity = math.Max(0, math.Min(1, intensity))
+}
+
+// Update advances crossfades and follows mixer changes. Call once per tick.
+func (m *MusicPlayer) Update() {
+	step := 1.0 / musicFadeFrames
+
+	if m.current != nil {
+		m.current.fade = math.Min(1, m.current.fade+step)
+		m.applyVolume(m.current)
+	}
+
+	stillFading := m.outgoing[:0]
+	for _, voice := range m.outgoing {
+		voice.fade -= step
+		if voice.fade <= 0 {
+			voice.stop()
+			continue
+		}
+		m.applyVolume(voice)
+		stillFading = append(stillFading, voice)
+	}
+	m.outgoing = stillFading
+}
+
+func (m *MusicPlayer) applyVolume(voice *musicVoice) {
+	volume := voice.fade * musicGain * m.mixer.Volume(BusMusic)
+	voice.base.SetVolume(volume)
+	if voice.layer != nil {
+		voice.layer.SetVolume(volume * m.intensity)
+	}
+}
+
+func (v *musicVoice) stop() {
+	if v.base != nil {
+		v.base.Pause()
+	}
+	if v.layer != nil {
+		v.layer.Pause()
+	}
+}
+
+// renderPattern synthesizes one pass of a pattern as 16-bit stereo PCM.
+func renderPattern(sampleRate int, pattern musicPattern) []byte {
+	stepSamples := int(60 / pattern.bpm * pattern.stepBeat * float64(sampleRate))
+	pcm := make([]byte, stepSamples*len(pattern.notes)*4)
+
+	for step, note := range pattern.notes {
+		if note == 0 {
+			continue // Rest - buffer is already silent
+		}
+		freq := 440 * math.Pow(2, float64(note-69)/12)
+
+		for i := 0; i < stepSamples; i++ {
+			t := float64(i) / float64(sampleRate)
+			phase := math.Mod(freq*t, 1)
+
+			var value float64
+			switch pattern.wave {
+			case waveTriangle:
+				value = 4*math.Abs(phase-0.5) - 1
+			default:
+				value = 1
+				if phase >= 0.5 {
+					value = -1
+				}
+			}
+
+			// Short attack and release so steps don't click
+			envelope := math.Min(1, math.Min(float64(i), float64(stepSamples-i))/200)
+			game.PutStereoSample(pcm, step*stepSamples+i, int16(value*envelope*0.5*math.MaxInt16))
+		}
+	}
+	return pcm
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"invaders/game"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+type optionKind int
+
+const (
+	optionBus       optionKind = iota // Adjusts a mixer bus
+	optionTheme                       // Cycles the color theme
+	optionShields                     // Cycles the shield restore rule
+	optionBonusLife                   // Cycles the extra life milestones
+)
+
+type optionItem struct {
+	label string
+	kind  optionKind
+	bus   Bus
+}
+
+var optionItems = []optionItem{
+	{label: "Master", bus: BusMaster},
+	{label: "Effects", bus: BusSFX},
+	{label: "Music", bus: BusMusic},
+	{label: "Theme", kind: optionTheme},
+	{label: "Shields", kind: optionShields},
+	{label: "Extra", kind: optionBonusLife},
+}
+
+type OptionsScene struct {
+	sceneManager *SceneManager
+	titleFont    *text.GoTextFace
+	itemFont     *text.GoTextFace
+	selected     int
+}
+
+func (o *OptionsScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{10, 15, 25, 255})
+
+	// Get screen dimensions
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+
+	// Draw title
+	titleText := "OPTIONS"
+	titleBounds, _ := text.Measure(titleText, o.titleFont, 0)
+	titleX := (w - int(titleBounds)) / 2
+	titleY := h/2 - 120
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(titleX), float64(titleY))
+	op.ColorScale.ScaleWithColor(color.RGBA{220, 220, 255, 255})
+	text.Draw(screen, titleText, o.titleFont, op)
+
+	// Draw one line per mixer bus, e.g. "> Music   [#######---]  70%", then the choices
+	for i, item := range optionItems {
+		cursor := "  "
+		itemColor := color.RGBA{180, 180, 200, 255}
+		if i == o.selected {
+			cursor = "> "
+			itemColor = color.RGBA{255, 200, 100, 255}
+		}
+
+		var itemText string
+		switch item.kind {
+		case optionTheme:
+			itemText = fmt.Sprintf("%s%-8s < %s >", cursor, item.label, o.sceneManager.settings.Theme.Theme().Name)
+		case optionShields:
+			itemText = fmt.Sprintf("%s%-8s < %s >", cursor, item.label, o.sceneManager.settings.ShieldRestore)
+		case optionBonusLife:
+			itemText = fmt.Sprintf("%s%-8s < %s >", cursor, item.label, o.sceneManager.settings.BonusLife)
+		default:
+			level := o.sceneManager.settings.Mixer.Bus(item.bus)
+			filled := int(level.Level*10 + 0.5)
+			bar := strings.Repeat("#", filled) + strings.Repeat("-", 10-filled)
+
+			status := fmt.Sprintf("%3.0f%%", level.Level*100)
+			if level.Muted {
+				status = "MUTED"
+			}
+			itemText = fmt.Sprintf("%s%-8s [%s] %s", cursor, item.label, bar, status)
+		}
+		itemBounds, _ := text.Measure(itemText, o.itemFont, 0)
+
+		itemOp := &text.DrawOptions{}
+		itemOp.GeoM.Translate(float64((w-int(itemBounds))/2), float64(titleY+80+i*40))
+		itemOp.ColorScale.ScaleWithColor(itemColor)
+		text.Draw(screen, itemText, o.itemFont, itemOp)
+	}
+
+	// Draw controls help
+	helpText := "Up/Down select  Left/Right adjust  Enter mute  Esc back"
+	helpBounds, _ := text.Measure(helpText, o.itemFont, 0)
+
+	helpOp := &text.DrawOptions{}
+	helpOp.GeoM.Translate(float64((w-int(helpBounds))/2), float64(titleY+80+len(optionItems)*40+40))
+	helpOp.ColorScale.ScaleWithColor(color.RGBA{130, 130, 160, 255})
+	text.Draw(screen, helpText, o.itemFont, helpOp)
+}
+
+func (o *OptionsScene) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || inpututil.IsKeyJustPressed(ebiten.KeyW) {
+		o.selected = (o.selected + len(optionItems) - 1) % len(optionItems)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		o.selected = (o.selected + 1) % len(optionItems)
+	}
+
+	settings := o.sceneManager.settings
+	item := optionItems[o.selected]
+	changed := false
+
+	direction := 0
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || inpututil.IsKeyJustPressed(ebiten.KeyA) {
+		direction = -1
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || inpututil.IsKeyJustPressed(ebiten.KeyD) {
+		direction = 1
+	}
+
+	switch item.kind {
+	case optionTheme:
+		if direction != 0 {
+			settings.Theme = (settings.Theme + ThemeID(direction) + themeCount) % themeCount
+			changed = true
+		}
+	case optionShields:
+		if direction != 0 {
+			settings.ShieldRestore = (settings.ShieldRestore + game.ShieldRestore(direction) + game.ShieldRestoreCount) % game.ShieldRestoreCount
+			changed = true
+		}
+	case optionBonusLife:
+		if direction != 0 {
+			settings.BonusLife = cycleBonusLife(settings.BonusLife, direction)
+			changed = true
+		}
+	default:
+		if direction != 0 {
+			settings.Mixer.Adjust(item.bus, float64(direction)*mixerStep)
+			changed = true
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+			settings.Mixer.ToggleMute(item.bus)
+			changed = true
+		}
+	}
+
+	if changed {
+		o.sceneManager.ApplySettings()
+	}
+
+	// Wait for release so the title screen doesn't see Escape held and start a game
+	if inpututil.IsKeyJustReleased(ebiten.KeyEscape) {
+		o.sceneManager.TransitionTo(SceneTitleScreen)
+	}
+
+	return nil
+}
+
+func (o *OptionsScene) Layout(outerWidth, outerHeight int) (int, int) {
+	return outerWidth, outerHeight
+}
+
+func NewOptionsScene(sm *SceneManager) *OptionsScene {
+	// Create fonts (same pattern as TitleScene)
+	titleFontSource, _ := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+	titleFont := &text.GoTextFace{
+		Source: titleFontSource,
+		Size:   48,
+	}
+
+	itemFontSource, _ := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+	itemFont := &text.GoTextFace{
+		Source: itemFontSource,
+		Size:   20,
+	}
+
+	return &OptionsScene{
+		sceneManager: sm,
+		titleFont:    titleFont,
+		itemFont:     itemFont,
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/binary"
+	"invaders/game"
+	"io"
+	"math"
+	"sync/atomic"
+)
+
+// panForX maps an emitter's horizontal center in the 320px playfield to a
+// stereo pan, -1 for hard left through 1 for hard right.
+func panForX(x float64) float64 {
+	return math.Max(-1, math.Min(1, x/(game.Width/2)-1))
+}
+
+// PannedStream applies a stereo pan to 16-bit stereo PCM as it is read. The
+// pan can be changed while the stream plays.
+type PannedStream struct {
+	src io.ReadSeeker
+	pan atomic.Uint64 // math.Float64bits of the pan, read from the audio goroutine
+}
+
+func NewPannedStream(src io.ReadSeeker) *PannedStream {
+	return &PannedStream{src: src}
+}
+
+func (s *PannedStream) SetPan(pan float64) {
+	s.pan.Store(math.Float64bits(pan))
+}
+
+func (s *PannedStream) Read(p []byte) (int, error) {
+	// Only read whole frames so every read starts on a left sample
+	p = p[:len(p)/4*4]
+	n, err := s.src.Read(p)
+
+	pan := math.Float64frombits(s.pan.Load())
+	if pan == 0 {
+		return n, err
+	}
+
+	// Equal-power pan, normalised so the center stays at full volume
+	angle := (pan + 1) * math.Pi / 4
+	leftGain := math.Min(1, math.Sqrt2*math.Cos(angle))
+	rightGain := math.Min(1, math.Sqrt2*math.Sin(angle))
+
+	for i := 0; i+4 <= n; i += 4 {
+		left := int16(binary.LittleEndian.Uint16(p[i:]))
+		right := int16(binary.LittleEndian.Uint16(p[i+2:]))
+		binary.LittleEndian.PutUint16(p[i:], uint16(int16(float64(left)*leftGain)))
+		binary.LittleEndian.PutUint16(p[i+2:], uint16(int16(float64(right)*rightGain)))
+	}
+	return n, err
+}
+
+func (s *PannedStream) Seek(offset int64, whence int) (int64, error) {
+	return s.src.Seek(offset, whence)
+}
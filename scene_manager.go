@@ -1,6 +1,13 @@
 package main
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"invaders/game"
+	"invaders/leaderboard"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
 
 type SceneType int
 
@@ -8,6 +15,7 @@ const (
 	SceneTitleScreen SceneType = iota
 	SceneGame
 	SceneEndScreen
+	SceneOptions
 )
 
 type Scene interface {
@@ -22,12 +30,55 @@ type SceneManager struct {
 	titleScene   *TitleScene
 	gameScene    *GameScene
 	endScene     *EndScene
+	optionsScene *OptionsScene
+	settings     *Settings
+	highScores   *HighScoreTable
+	sounds       Sounds
+	music        Music
+	hotReload    *HotReloader        // Set in -dev mode
+	drawStats    *DrawStats          // Set with -draw-stats
+	leaderboard  *leaderboard.Client // Set with -leaderboard
 }
 
 func (sm *SceneManager) Update() error {
+	sm.handleMuteKeys()
+	sm.music.Update()
+	if sm.hotReload != nil {
+		sm.hotReload.Update()
+	}
 	return sm.currentScene.Update()
 }
 
+// handleMuteKeys lets the player mute buses from any scene:
+// M for everything, F7 for sound effects and F8 for music.
+func (sm *SceneManager) handleMuteKeys() {
+	changed := false
+	if inpututil.IsKeyJustPressed(ebiten.KeyM) {
+		sm.settings.Mixer.ToggleMute(BusMaster)
+		changed = true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF7) {
+		sm.settings.Mixer.ToggleMute(BusSFX)
+		changed = true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF8) {
+		sm.settings.Mixer.ToggleMute(BusMusic)
+		changed = true
+	}
+
+	if changed {
+		sm.ApplySettings()
+	}
+}
+
+// ApplySettings pushes changed settings to the audio system and saves them.
+func (sm *SceneManager) ApplySettings() {
+	sm.sounds.ApplyMixer()
+	if err := sm.settings.Save(); err != nil {
+		log.Printf("Error saving settings: %v", err)
+	}
+}
+
 func (sm *SceneManager) Draw(screen *ebiten.Image) {
 	sm.currentScene.Draw(screen)
 }
@@ -46,29 +97,65 @@ func (sm *SceneManager) TransitionTo(sceneType SceneType) {
 		sm.currentScene = sm.gameScene
 	case SceneEndScreen:
 		sm.currentScene = sm.endScene
+	case SceneOptions:
+		sm.currentScene = sm.optionsScene
+	}
+
+	sm.music.PlayTrack(musicForScene(sceneType))
+}
+
+// StartGame begins a new game, so it plays by the settings as they are now.
+func (sm *SceneManager) StartGame() {
+	sm.gameScene = NewGameScene(sm)
+	sm.TransitionTo(SceneGame)
+}
+
+// musicForScene picks the track that loops behind each scene.
+func musicForScene(sceneType SceneType) MusicTrack {
+	switch sceneType {
+	case SceneGame:
+		return MusicGame
+	case SceneEndScreen:
+		return MusicGameOver
+	default:
+		return MusicTitle
 	}
 }
 
-func (sm *SceneManager) TransitionToEndScreen(finalScore int) {
+// TransitionToEndScreen shows the result of a game, with the seed and replay
+// that let the leaderboard check it.
+func (sm *SceneManager) TransitionToEndScreen(finalScore, wave int, seed int64, replay *game.Replay) {
 	sm.sceneType = SceneEndScreen
-	sm.endScene = NewEndScene(sm, finalScore)
+	sm.endScene = NewEndScene(sm, finalScore, wave)
+	sm.endScene.seed = seed
+	sm.endScene.replay = replay
+
+	// Assets may have changed mid-game, so the leaderboard couldn't re-simulate it
+	replay.Dev = sm.hotReload != nil
 	sm.currentScene = sm.endScene
+	sm.music.PlayTrack(MusicGameOver)
 }
 
 func (sm *SceneManager) GetCurrentSceneType() SceneType {
 	return sm.sceneType
 }
 
-func NewSceneManager() *SceneManager {
+func NewSceneManager(settings *Settings, highScores *HighScoreTable, sounds Sounds, music Music) *SceneManager {
 	sm := &SceneManager{
-		sceneType: SceneTitleScreen,
+		sceneType:  SceneTitleScreen,
+		settings:   settings,
+		highScores: highScores,
+		sounds:     sounds,
+		music:      music,
 	}
 
 	sm.titleScene = NewTitleScene(sm)
 	sm.gameScene = NewGameScene(sm)
-	sm.endScene = NewEndScene(sm, 0) // Default score of 0
+	sm.endScene = NewEndScene(sm, 0, 0) // Default score of 0
+	sm.optionsScene = NewOptionsScene(sm)
 
 	sm.currentScene = sm.titleScene
+	sm.music.PlayTrack(MusicTitle)
 
 	return sm
 }
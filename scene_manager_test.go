@@ -0,0 +1,24 @@
+package main
+
+import (
+	"invaders/game"
+	"testing"
+)
+
+func TestStartGameUsesSettings(t *testing.T) {
+	recorder := NewRecordingAudio()
+	settings := NewSettings()
+	sm := NewSceneManager(settings, &HighScoreTable{}, recorder, recorder)
+
+	// Changed in Options after the scene manager already built a game
+	settings.ShieldRestore = game.RestoreFully
+	settings.BonusLife = bonusLifePresets[len(bonusLifePresets)-1]
+
+	sm.StartGame()
+	if sm.GetCurrentSceneType() != SceneGame {
+		t.Fatalf("scene is %v, want the game", sm.GetCurrentSceneType())
+	}
+	if got := sm.gameScene.replay.Options; got != settings.GameOptions() {
+		t.Errorf("game plays with %+v, want %+v", got, settings.GameOptions())
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"invaders/game"
+	"os"
+	"path/filepath"
+)
+
+const settingsFileName = "settings.json"
+
+// Settings holds the player-selectable options that outlive a single GameScene.
+type Settings struct {
+	MarchMode game.MarchMode `json:"-"` // Chosen per run with the -ripple flag
+	Mixer     Mixer          `json:"mixer"`
+	Theme     ThemeID        `json:"theme"`
+	// How shields are repaired when a wave is cleared
+	ShieldRestore game.ShieldRestore `json:"shieldRestore"`
+	// Scores at which an extra life is awarded
+	BonusLife game.BonusLife `json:"bonusLife"`
+}
+
+func NewSettings() *Settings {
+	return &Settings{
+		MarchMode: game.MarchFleet,
+		Mixer:     NewMixer(),
+		Theme:     ThemeOriginal,
+
+		ShieldRestore: game.RestoreNever,
+		BonusLife:     bonusLifePresets[0],
+	}
+}
+
+// GameOptions returns the settings that change the rules of a game.
+func (s *Settings) GameOptions() game.Options {
+	return game.Options{
+		MarchMode:     s.MarchMode,
+		ShieldRestore: s.ShieldRestore,
+		BonusLife:     s.BonusLife,
+	}
+}
+
+// settingsPath returns where settings are persisted in the user config directory.
+func settingsPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "invaders", settingsFileName), nil
+}
+
+// LoadSettings reads saved settings, falling back to defaults if none have been saved yet.
+func LoadSettings() (*Settings, error) {
+	settings := NewSettings()
+
+	path, err := settingsPath()
+	if err != nil {
+		return settings, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	if err := json.Unmarshal(data, settings); err != nil {
+		return NewSettings(), err
+	}
+	return settings, nil
+}
+
+func (s *Settings) Save() error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
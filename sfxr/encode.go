@@ -0,0 +1,61 @@
+package sfxr
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// toInt16 clips a sample to -1..1 and scales it to 16 bits.
+func toInt16(sample float64) int16 {
+	return int16(math.Max(-1, math.Min(1, sample)) * math.MaxInt16)
+}
+
+// PCM converts mono samples to 16-bit little-endian stereo, the format
+// ebiten's audio players expect.
+func PCM(samples []float64) []byte {
+	pcm := make([]byte, len(samples)*4)
+	for i, sample := range samples {
+		value := uint16(toInt16(sample))
+		binary.LittleEndian.PutUint16(pcm[i*4:], value)
+		binary.LittleEndian.PutUint16(pcm[i*4+2:], value)
+	}
+	return pcm
+}
+
+// WriteWAV writes mono samples as a 16-bit PCM WAV file.
+func WriteWAV(w io.Writer, samples []float64, sampleRate int) error {
+	const (
+		channels      = 1
+		bitsPerSample = 16
+	)
+	dataSize := uint32(len(samples) * 2)
+	blockAlign := uint16(channels * bitsPerSample / 8)
+
+	header := []any{
+		[4]byte{'R', 'I', 'F', 'F'},
+		36 + dataSize,
+		[4]byte{'W', 'A', 'V', 'E'},
+		[4]byte{'f', 'm', 't', ' '},
+		uint32(16), // fmt chunk size
+		uint16(1),  // PCM
+		uint16(channels),
+		uint32(sampleRate),
+		uint32(sampleRate) * uint32(blockAlign),
+		blockAlign,
+		uint16(bitsPerSample),
+		[4]byte{'d', 'a', 't', 'a'},
+		dataSize,
+	}
+	for _, field := range header {
+		if err := binary.Write(w, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+
+	data := make([]int16, len(samples))
+	for i, sample := range samples {
+		data[i] = toInt16(sample)
+	}
+	return binary.Write(w, binary.LittleEndian, data)
+}
@@ -0,0 +1,101 @@
+package sfxr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Preset names for the game's effects, used as keys in config files.
+const (
+	Laser     = "laser"
+	Explosion = "explosion"
+	Death     = "death"
+	UFOWarble = "ufo"
+	WaveClear = "waveClear"
+)
+
+// Presets returns the built-in parameters for every game effect.
+func Presets() map[string]Params {
+	return map[string]Params{
+		Laser: {
+			Wave:      Square,
+			BaseFreq:  1200,
+			MinFreq:   150,
+			FreqSlide: -4,
+			Duty:      0.3,
+			DutySweep: 0.5,
+			Sustain:   0.05,
+			Decay:     0.15,
+			LowPass:   1,
+			Volume:    0.4,
+		},
+		Explosion: {
+			Wave:      Noise,
+			BaseFreq:  900,
+			FreqSlide: -1.5,
+			Sustain:   0.1,
+			Punch:     0.6,
+			Decay:     0.4,
+			LowPass:   0.8,
+			Volume:    0.5,
+			Seed:      1,
+		},
+		Death: {
+			Wave:         Sawtooth,
+			BaseFreq:     440,
+			MinFreq:      40,
+			FreqSlide:    -1.2,
+			VibratoDepth: 0.15,
+			VibratoSpeed: 14,
+			Sustain:      0.4,
+			Decay:        0.6,
+			LowPass:      0.7,
+			Volume:       0.4,
+		},
+		UFOWarble: {
+			// Whole vibrato cycles with no attack or decay, so the sound loops cleanly
+			Wave:         Sine,
+			BaseFreq:     620,
+			VibratoDepth: 0.25,
+			VibratoSpeed: 8,
+			Sustain:      0.5,
+			LowPass:      1,
+			Volume:       0.5,
+		},
+		WaveClear: {
+			// A bright rising sweep, rewarding without drowning the next wave's march
+			Wave:      Square,
+			BaseFreq:  330,
+			FreqSlide: 2.5,
+			Duty:      0.5,
+			Sustain:   0.3,
+			Decay:     0.35,
+			LowPass:   0.9,
+			Volume:    0.35,
+		},
+	}
+}
+
+// LoadConfig reads a JSON object of named parameter sets. Each entry starts
+// from the preset of the same name, so a config only needs the fields it tweaks.
+func LoadConfig(r io.Reader) (map[string]Params, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	presets := Presets()
+	for name, data := range raw {
+		params := presets[name]
+		if err := json.Unmarshal(data, &params); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		presets[name] = params
+	}
+
+	return presets, nil
+}
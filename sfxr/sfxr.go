@@ -0,0 +1,144 @@
+// Package sfxr renders retro sound effects from small parameter sets, in the
+// spirit of DrPetter's sfxr. Effects are described by Params, rendered to mono
+// samples and converted to PCM or WAV for playback and review.
+package sfxr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+type Wave string
+
+const (
+	Square   Wave = "square"
+	Sawtooth Wave = "sawtooth"
+	Sine     Wave = "sine"
+	Noise    Wave = "noise"
+)
+
+// Params describes one sound effect. Durations are in seconds and slides in
+// octaves per second.
+type Params struct {
+	Wave         Wave    `json:"wave"`
+	BaseFreq     float64 `json:"baseFreq"`     // Starting pitch in Hz
+	MinFreq      float64 `json:"minFreq"`      // The sound stops if a slide drops below this
+	FreqSlide    float64 `json:"freqSlide"`    // Pitch change in octaves per second
+	FreqAccel    float64 `json:"freqAccel"`    // Change in FreqSlide per second
+	VibratoDepth float64 `json:"vibratoDepth"` // Fraction of the pitch to wobble by
+	VibratoSpeed float64 `json:"vibratoSpeed"` // Wobbles per second
+	Duty         float64 `json:"duty"`         // Square wave duty cycle, 0-1
+	DutySweep    float64 `json:"dutySweep"`    // Change in Duty per second
+	Attack       float64 `json:"attack"`
+	Sustain      float64 `json:"sustain"`
+	Punch        float64 `json:"punch"` // Extra volume at the start of the sustain, 0-1
+	Decay        float64 `json:"decay"`
+	LowPass      float64 `json:"lowPass"` // Filter cutoff, 0-1 where 1 leaves the sound untouched
+	Volume       float64 `json:"volume"`
+	Seed         int64   `json:"seed"` // Noise seed so renders are repeatable
+}
+
+// Validate reports parameters that can't produce a sound.
+func (p Params) Validate() error {
+	var errs []error
+
+	switch p.Wave {
+	case Square, Sawtooth, Sine, Noise:
+	default:
+		errs = append(errs, fmt.Errorf("unknown wave %q", p.Wave))
+	}
+	if p.BaseFreq <= 0 {
+		errs = append(errs, errors.New("baseFreq must be positive"))
+	}
+	if p.Attack < 0 || p.Sustain < 0 || p.Decay < 0 {
+		errs = append(errs, errors.New("attack, sustain and decay can't be negative"))
+	}
+	if p.Attack+p.Sustain+p.Decay <= 0 {
+		errs = append(errs, errors.New("sound has no length"))
+	}
+	if p.LowPass < 0 || p.LowPass > 1 {
+		errs = append(errs, errors.New("lowPass must be between 0 and 1"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Duration returns the length of the rendered sound in seconds.
+func (p Params) Duration() float64 {
+	return p.Attack + p.Sustain + p.Decay
+}
+
+// Render synthesizes the effect as mono samples between -1 and 1.
+func Render(p Params, sampleRate int) []float64 {
+	samples := make([]float64, int(p.Duration()*float64(sampleRate)))
+	noise := rand.New(rand.NewSource(p.Seed))
+	noiseValue := 0.0
+
+	dt := 1 / float64(sampleRate)
+	phase := 0.0
+	filtered := 0.0
+
+	for i := range samples {
+		t := float64(i) * dt
+
+		// Pitch: slide and accelerate in octaves, then wobble
+		octaves := p.FreqSlide*t + 0.5*p.FreqAccel*t*t
+		freq := p.BaseFreq * math.Pow(2, octaves)
+		if p.MinFreq > 0 && freq < p.MinFreq {
+			return samples[:i]
+		}
+		if p.VibratoDepth > 0 {
+			freq *= 1 + p.VibratoDepth*math.Sin(2*math.Pi*p.VibratoSpeed*t)
+		}
+
+		phase += freq * dt
+		if phase >= 1 {
+			phase -= math.Floor(phase)
+			// Noise holds a random value for each period, like sfxr
+			noiseValue = noise.Float64()*2 - 1
+		}
+
+		var value float64
+		switch p.Wave {
+		case Square:
+			duty := math.Max(0, math.Min(1, p.Duty+p.DutySweep*t))
+			value = 1
+			if phase >= duty {
+				value = -1
+			}
+		case Sawtooth:
+			value = 1 - 2*phase
+		case Sine:
+			value = math.Sin(2 * math.Pi * phase)
+		case Noise:
+			value = noiseValue
+		}
+
+		if p.LowPass > 0 && p.LowPass < 1 {
+			filtered += (value - filtered) * p.LowPass * p.LowPass
+			value = filtered
+		}
+
+		samples[i] = value * envelope(p, t) * p.Volume
+	}
+
+	return samples
+}
+
+// envelope returns the volume at time t of the attack, sustain and decay stages.
+func envelope(p Params, t float64) float64 {
+	switch {
+	case t < p.Attack:
+		return t / p.Attack
+	case t < p.Attack+p.Sustain:
+		// Punch starts loud and settles back to full volume over the sustain
+		progress := (t - p.Attack) / p.Sustain
+		return 1 + p.Punch*(1-progress)
+	case p.Decay > 0:
+		return math.Max(0, 1-(t-p.Attack-p.Sustain)/p.Decay)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"invaders/assets"
+	"invaders/game"
+	"invaders/sfxr"
+	"io"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+)
+
+type soundDef struct {
+	synth     string // Name of the sfxr effect that can replace the OGG
+	maxVoices int    // How many copies of the sound may play at once
+}
+
+var soundDefs = map[game.SoundID]soundDef{
+	game.SoundPlayerShoot:    {synth: sfxr.Laser, maxVoices: 2},
+	game.SoundAlienExplosion: {synth: sfxr.Explosion, maxVoices: 4},
+	game.SoundPlayerDeath:    {synth: sfxr.Death, maxVoices: 1},
+	game.SoundUFO:            {synth: sfxr.UFOWarble, maxVoices: 1},
+	game.SoundWaveClear:      {synth: sfxr.WaveClear, maxVoices: 1},
+}
+
+// maxPCMVoices is how many sounds generated at runtime, like march notes,
+// jingles and clanks, may play at once.
+const maxPCMVoices = 3
+
+// encodedSounds returns the OGG data for each sound that has one. It must be
+// called after assets.Load has filled in the sounds. Sounds without an OGG
+// play their sfxr preset.
+func encodedSounds() map[game.SoundID][]byte {
+	return map[game.SoundID][]byte{
+		game.SoundPlayerShoot:    assets.PlayerShootSound,
+		game.SoundAlienExplosion: assets.AlienExplosionSound,
+		game.SoundPlayerDeath:    assets.PlayerDeathSound,
+		game.SoundUFO:            assets.UFOSound,
+	}
+}
+
+// SoundBank decodes every sound effect once and plays them from memory,
+// reusing a capped set of voices per sound.
+type SoundBank struct {
+	context   *audio.Context
+	mixer     *Mixer
+	pcm       map[game.SoundID][]byte
+	voices    map[game.SoundID][]*soundVoice
+	nextVoice map[game.SoundID]int // Voice to steal when every voice is busy
+	loops     map[*bankLoop]bool
+	synth     map[game.SoundID][]byte // Rendered -sfx effects, which win over the OGGs
+
+	pcmVoices    []*soundVoice // Voices for sounds generated at runtime
+	nextPCMVoice int           // PCM voice to steal when every one is busy
+}
+
+type soundVoice struct {
+	player *audio.Player
+	stream *PannedStream
+	source *pcmSource // Generated sound the voice plays, for PCM voices only
+}
+
+// pcmSource holds the samples a PCM voice plays. Each PlayPCM loads new ones,
+// which may happen while the audio goroutine is still reading the last.
+type pcmSource struct {
+	mu     sync.Mutex
+	reader bytes.Reader
+}
+
+func (s *pcmSource) Load(pcm []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reader.Reset(pcm)
+}
+
+func (s *pcmSource) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reader.Read(p)
+}
+
+func (s *pcmSource) Seek(offset int64, whence int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reader.Seek(offset, whence)
+}
+
+type bankLoop struct {
+	sound  game.SoundID
+	player *audio.Player
+	stream *PannedStream
+	gain   float64 // Volume before mixing
+}
+
+// NewSoundBank decodes every sound effect. Sounds that fail to decode play as
+// silence, and all of the failures are returned together, so the bank can
+// still be used when the error isn't fatal.
+func NewSoundBank(context *audio.Context, mixer *Mixer) (*SoundBank, error) {
+	bank := &SoundBank{
+		context:   context,
+		mixer:     mixer,
+		voices:    make(map[game.SoundID][]*soundVoice),
+		nextVoice: make(map[game.SoundID]int),
+		loops:     make(map[*bankLoop]bool),
+	}
+
+	return bank, bank.decode()
+}
+
+// decode turns every sound asset into PCM, replacing any that fail with silence.
+func (b *SoundBank) decode() error {
+	var errs []error
+	pcm := make(map[game.SoundID][]byte)
+	encoded := encodedSounds()
+
+	for id, def := range soundDefs {
+		data, ok := encoded[id]
+		if !ok {
+			pcm[id] = sfxr.PCM(sfxr.Render(sfxr.Presets()[def.synth], b.context.SampleRate()))
+			continue
+		}
+		if len(data) == 0 {
+			// Missing assets play as a short silence
+			pcm[id] = b.silence()
+			continue
+		}
+
+		decoded, err := b.decodeOgg(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("decoding sound %d: %w", id, err))
+			decoded = b.silence()
+		}
+		pcm[id] = decoded
+	}
+
+	for id, synth := range b.synth {
+		pcm[id] = synth
+	}
+
+	b.pcm = pcm
+	return errors.Join(errs...)
+}
+
+func (b *SoundBank) decodeOgg(data []byte) ([]byte, error) {
+	stream, err := vorbis.DecodeWithSampleRate(b.context.SampleRate(), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(stream)
+}
+
+// silence is a tenth of a second of quiet, standing in for sounds that can't be played.
+func (b *SoundBank) silence() []byte {
+	return make([]byte, b.context.SampleRate()/10*4)
+}
+
+// Reload re-decodes the sound assets after they change on disk. Voices
+// already playing finish with the old sound; sounds that no longer decode
+// are silent until they're fixed.
+func (b *SoundBank) Reload() error {
+	err := b.decode()
+	b.voices = make(map[game.SoundID][]*soundVoice)
+	b.nextVoice = make(map[game.SoundID]int)
+	return err
+}
+
+func (b *SoundBank) SampleRate() int {
+	return b.context.SampleRate()
+}
+
+// LoadSynthConfig replaces the embedded OGGs with effects rendered from an
+// sfxr config file, including after a Reload. Must be called before any sound plays.
+func (b *SoundBank) LoadSynthConfig(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	effects, err := sfxr.LoadConfig(file)
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", path, err)
+	}
+
+	b.synth = make(map[game.SoundID][]byte)
+	for id, def := range soundDefs {
+		b.synth[id] = sfxr.PCM(sfxr.Render(effects[def.synth], b.context.SampleRate()))
+		b.pcm[id] = b.synth[id]
+	}
+	return nil
+}
+
+// Play starts a one-shot sound in the center of the stereo field.
+func (b *SoundBank) Play(id game.SoundID) {
+	b.PlayAt(id, game.Width/2)
+}
+
+// PlayAt starts a one-shot sound panned to an emitter at playfield X. If every
+// voice for the sound is busy, one is cut off and restarted, taking turns so
+// the same voice isn't cut every time. Loops of the sound take up voices too.
+func (b *SoundBank) PlayAt(id game.SoundID, x float64) {
+	voices := b.voices[id]
+
+	for _, voice := range voices {
+		if !voice.player.IsPlaying() {
+			b.restart(voice, x)
+			return
+		}
+	}
+
+	if len(voices)+b.loopCount(id) < soundDefs[id].maxVoices {
+		stream := NewPannedStream(bytes.NewReader(b.pcm[id]))
+		player, err := b.context.NewPlayer(stream)
+		if err != nil {
+			log.Printf("Error creating audio player for sound %d: %v", id, err)
+			return
+		}
+		voice := &soundVoice{player: player, stream: stream}
+		b.voices[id] = append(voices, voice)
+		b.restart(voice, x)
+		return
+	}
+
+	// Loops can't be stolen from, so with every voice looping the sound is dropped
+	if len(voices) == 0 {
+		return
+	}
+
+	// All voices busy - steal them round robin
+	index := b.nextVoice[id]
+	b.nextVoice[id] = (index + 1) % len(voices)
+	b.restart(voices[index], x)
+}
+
+// PlayPCM plays sound generated at runtime through the SFX bus. Generated
+// sounds share maxPCMVoices voices; when all are busy, one is cut off and
+// restarted, taking turns like PlayAt.
+func (b *SoundBank) PlayPCM(pcm []byte) {
+	voice := b.pcmVoice()
+	if voice == nil {
+		return
+	}
+	voice.player.Pause()
+	voice.source.Load(pcm)
+	b.restart(voice, game.Width/2)
+}
+
+// pcmVoice returns an idle PCM voice, a new one while there are fewer than
+// maxPCMVoices, or the next one to steal. It returns nil if a voice can't be made.
+func (b *SoundBank) pcmVoice() *soundVoice {
+	for _, voice := range b.pcmVoices {
+		if !voice.player.IsPlaying() {
+			return voice
+		}
+	}
+
+	if len(b.pcmVoices) < maxPCMVoices {
+		source := &pcmSource{}
+		stream := NewPannedStream(source)
+		player, err := b.context.NewPlayer(stream)
+		if err != nil {
+			log.Printf("Error creating audio player for generated sound: %v", err)
+			return nil
+		}
+		voice := &soundVoice{player: player, stream: stream, source: source}
+		b.pcmVoices = append(b.pcmVoices, voice)
+		return voice
+	}
+
+	voice := b.pcmVoices[b.nextPCMVoice]
+	b.nextPCMVoice = (b.nextPCMVoice + 1) % len(b.pcmVoices)
+	return voice
+}
+
+// Loop starts a sound at the given gain that repeats until StopLoop is called.
+// Each loop takes one of the sound's voices until it stops, and it's an error
+// to start more loops than the sound has voices.
+func (b *SoundBank) Loop(id game.SoundID, gain float64) (game.SoundLoop, error) {
+	if loops := b.loopCount(id); loops >= soundDefs[id].maxVoices {
+		return nil, fmt.Errorf("sound %d is already looping on all %d of its voices", id, loops)
+	}
+
+	pcm := b.pcm[id]
+	stream := NewPannedStream(audio.NewInfiniteLoop(bytes.NewReader(pcm), int64(len(pcm))))
+
+	player, err := b.context.NewPlayer(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	loop := &bankLoop{sound: id, player: player, stream: stream, gain: gain}
+	b.loops[loop] = true
+	player.SetVolume(gain * b.mixer.Volume(BusSFX))
+	player.Play()
+	return loop, nil
+}
+
+func (b *SoundBank) StopLoop(loop game.SoundLoop) {
+	bl := loop.(*bankLoop)
+	bl.player.Pause()
+	delete(b.loops, bl)
+}
+
+// loopCount returns how many loops of a sound are playing.
+func (b *SoundBank) loopCount(id game.SoundID) int {
+	count := 0
+	for loop := range b.loops {
+		if loop.sound == id {
+			count++
+		}
+	}
+	return count
+}
+
+// SetPan moves a looping sound to follow its emitter at playfield X.
+func (l *bankLoop) SetPan(x float64) {
+	l.stream.SetPan(panForX(x))
+}
+
+// ApplyMixer updates every voice and loop after the mixer levels change.
+func (b *SoundBank) ApplyMixer() {
+	volume := b.mixer.Volume(BusSFX)
+	for _, voices := range b.voices {
+		for _, voice := range voices {
+			voice.player.SetVolume(volume)
+		}
+	}
+	for _, voice := range b.pcmVoices {
+		voice.player.SetVolume(volume)
+	}
+	for loop := range b.loops {
+		loop.player.SetVolume(loop.gain * volume)
+	}
+}
+
+func (b *SoundBank) restart(voice *soundVoice, x float64) {
+	voice.player.Pause()
+	_ = voice.player.Rewind()
+	voice.stream.SetPan(panForX(x))
+	voice.player.SetVolume(b.mixer.Volume(BusSFX))
+	voice.player.Play()
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"invaders/assets"
+	"invaders/game"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// testAudioContext is shared by every test, as a process may only make one.
+var testAudioContext = sync.OnceValue(func() *audio.Context {
+	return audio.NewContext(44100)
+})
+
+func TestSoundBankSilencesCorruptSounds(t *testing.T) {
+	original := assets.PlayerShootSound
+	assets.PlayerShootSound = []byte("OggS, but not really")
+	t.Cleanup(func() { assets.PlayerShootSound = original })
+
+	mixer := NewMixer()
+	bank, err := NewSoundBank(testAudioContext(), &mixer)
+	if err == nil {
+		t.Fatal("NewSoundBank accepted a corrupt OGG")
+	}
+	if pcm := bank.pcm[game.SoundPlayerShoot]; len(pcm) == 0 || !bytes.Equal(pcm, make([]byte, len(pcm))) {
+		t.Error("corrupt sound wasn't replaced with silence")
+	}
+	if pcm := bank.pcm[game.SoundAlienExplosion]; bytes.Equal(pcm, make([]byte, len(pcm))) {
+		t.Error("a good sound was silenced too")
+	}
+}
+
+func TestSoundBankKeepsSynthAfterReload(t *testing.T) {
+	mixer := NewMixer()
+	bank, err := NewSoundBank(testAudioContext(), &mixer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := filepath.Join(t.TempDir(), "sfx.json")
+	if err := os.WriteFile(config, []byte(`{"laser": {"baseFreq": 900}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := bank.LoadSynthConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	synth := bank.pcm[game.SoundPlayerShoot]
+
+	if err := bank.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bank.pcm[game.SoundPlayerShoot], synth) {
+		t.Error("Reload went back to the OGG instead of the -sfx effect")
+	}
+}
+
+func TestSoundBankLoopsUseVoices(t *testing.T) {
+	mixer := NewMixer()
+	bank, err := NewSoundBank(testAudioContext(), &mixer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limit := soundDefs[game.SoundAlienExplosion].maxVoices
+	var loops []game.SoundLoop
+	for range limit {
+		loop, err := bank.Loop(game.SoundAlienExplosion, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loops = append(loops, loop)
+	}
+	if _, err := bank.Loop(game.SoundAlienExplosion, 1); err == nil {
+		t.Errorf("Loop started more than the %d voices of a sound", limit)
+	}
+
+	// One-shots find no free voice and are dropped rather than added
+	bank.PlayAt(game.SoundAlienExplosion, 100)
+	if voices := len(bank.voices[game.SoundAlienExplosion]); voices != 0 {
+		t.Errorf("%d one-shot voices on top of %d loops", voices, limit)
+	}
+
+	bank.StopLoop(loops[0])
+	bank.PlayAt(game.SoundAlienExplosion, 100)
+	if voices := len(bank.voices[game.SoundAlienExplosion]); voices != 1 {
+		t.Errorf("%d one-shot voices after a loop stopped, want 1", voices)
+	}
+	for _, loop := range loops[1:] {
+		bank.StopLoop(loop)
+	}
+}
+
+func TestSoundBankPCMUsesVoices(t *testing.T) {
+	mixer := NewMixer()
+	bank, err := NewSoundBank(testAudioContext(), &mixer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second of tone, so every note is still playing when the next starts
+	note := make([]byte, bank.SampleRate()*4)
+	for i := range note {
+		note[i] = byte(i)
+	}
+	for range maxPCMVoices * 3 {
+		bank.PlayPCM(note)
+	}
+	if voices := len(bank.pcmVoices); voices != maxPCMVoices {
+		t.Errorf("%d voices for generated sounds, want %d", voices, maxPCMVoices)
+	}
+
+	mixer.ToggleMute(BusSFX)
+	bank.ApplyMixer()
+	for i, voice := range bank.pcmVoices {
+		if volume := voice.player.Volume(); volume != 0 {
+			t.Errorf("voice %d has volume %v with effects muted", i, volume)
+		}
+	}
+
+	bank.PlayPCM(note)
+	for i, voice := range bank.pcmVoices {
+		if volume := voice.player.Volume(); volume != 0 {
+			t.Errorf("voice %d started at volume %v with effects muted", i, volume)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package textures
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	atlasWidth   = 256
+	atlasPadding = 1 // Transparent gap between sprites so scaled draws don't bleed
+)
+
+// UseAtlas packs every sprite and HUD glyph into one texture when Load runs,
+// so consecutive draws share a source image and can be batched. Turn it off
+// to compare draw batches with one texture per file.
+var UseAtlas = true
+
+var (
+	atlasPixels *image.RGBA                        // CPU copy of the atlas for debug dumps
+	atlasRects  = make(map[string]image.Rectangle) // Where each named image sits in the atlas
+	textureIDs  = make(map[*ebiten.Image]int)      // Which texture each loaded image draws from
+)
+
+// TextureID identifies the GPU texture an asset image is drawn from. Draws
+// from the same texture in a row can be batched. Images that didn't come from
+// Load return -1.
+func TextureID(img *ebiten.Image) int {
+	if id, ok := textureIDs[img]; ok {
+		return id
+	}
+	return -1
+}
+
+// DumpAtlas writes the packed atlas as a PNG for debugging.
+func DumpAtlas(w io.Writer) error {
+	if atlasPixels == nil {
+		return errors.New("no atlas was built")
+	}
+	return png.Encode(w, atlasPixels)
+}
+
+type decodedImage struct {
+	name string
+	img  image.Image
+}
+
+// upload turns every decoded image into an ebiten image, packed into one
+// atlas texture unless UseAtlas is off.
+func (l *loader) upload() {
+	if !UseAtlas {
+		for i, decoded := range l.decoded {
+			img := ebiten.NewImageFromImage(decoded.img)
+			l.textures[decoded.name] = img
+			textureIDs[img] = i
+		}
+		return
+	}
+
+	sizes := make([]image.Point, len(l.decoded))
+	for i, decoded := range l.decoded {
+		sizes[i] = decoded.img.Bounds().Size()
+	}
+	positions, height := packShelves(sizes, atlasWidth, atlasPadding)
+
+	atlasPixels = image.NewRGBA(image.Rect(0, 0, atlasWidth, height))
+	for i, decoded := range l.decoded {
+		rect := image.Rectangle{Min: positions[i], Max: positions[i].Add(sizes[i])}
+		draw.Draw(atlasPixels, rect, decoded.img, decoded.img.Bounds().Min, draw.Src)
+		atlasRects[decoded.name] = rect
+	}
+
+	atlas := ebiten.NewImageFromImage(atlasPixels)
+	for name, rect := range atlasRects {
+		img := atlas.SubImage(rect).(*ebiten.Image)
+		l.textures[name] = img
+		textureIDs[img] = 0
+	}
+}
+
+// packShelves places rectangles in rows of a fixed-width texture, tallest
+// first, and returns each position and the height used.
+func packShelves(sizes []image.Point, width, padding int) ([]image.Point, int) {
+	order := make([]int, len(sizes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sizes[order[a]].Y > sizes[order[b]].Y
+	})
+
+	positions := make([]image.Point, len(sizes))
+	x, y, shelfHeight := padding, padding, 0
+	for _, i := range order {
+		size := sizes[i]
+		if x+size.X+padding > width {
+			// Start a new shelf below the tallest sprite on this one
+			x = padding
+			y += shelfHeight + padding
+			shelfHeight = 0
+		}
+		positions[i] = image.Pt(x, y)
+		x += size.X + padding
+		shelfHeight = max(shelfHeight, size.Y)
+	}
+
+	return positions, y + shelfHeight + padding
+}
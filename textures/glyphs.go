@@ -0,0 +1,79 @@
+package textures
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// HUDFontSize is the pixel size the HUD glyphs are rendered at.
+const HUDFontSize = 8
+
+// Glyph is one pre-rendered HUD character. The image spans the full line
+// height, so glyphs drawn at the same Y share a baseline.
+type Glyph struct {
+	Image   *ebiten.Image
+	Advance float64
+}
+
+// HUDGlyphs holds white printable-ASCII glyphs for the in-game HUD, tinted
+// when drawn. They are packed into the sprite atlas by Load.
+var HUDGlyphs map[rune]Glyph
+
+func glyphName(r rune) string {
+	return fmt.Sprintf("glyph:%d", r)
+}
+
+// decodeGlyphs rasterizes the HUD font so its glyphs can join the atlas.
+func (l *loader) decodeGlyphs() map[rune]float64 {
+	advances := make(map[rune]float64)
+
+	parsed, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("HUD font: %w", err))
+		return advances
+	}
+	face, err := opentype.NewFace(parsed, &opentype.FaceOptions{Size: HUDFontSize, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("HUD font: %w", err))
+		return advances
+	}
+	defer face.Close()
+
+	metrics := face.Metrics()
+	lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
+
+	for r := rune(' '); r <= '~'; r++ {
+		advance, ok := face.GlyphAdvance(r)
+		if !ok {
+			continue
+		}
+		width := max(1, advance.Ceil())
+
+		glyphImage := image.NewRGBA(image.Rect(0, 0, width, lineHeight))
+		drawer := &font.Drawer{
+			Dst:  glyphImage,
+			Src:  image.White,
+			Face: face,
+			Dot:  fixed.Point26_6{Y: metrics.Ascent},
+		}
+		drawer.DrawString(string(r))
+
+		l.decoded = append(l.decoded, decodedImage{name: glyphName(r), img: glyphImage})
+		advances[r] = float64(advance) / 64
+	}
+	return advances
+}
+
+func (l *loader) glyphs(advances map[rune]float64) map[rune]Glyph {
+	glyphs := make(map[rune]Glyph, len(advances))
+	for r, advance := range advances {
+		glyphs[r] = Glyph{Image: l.textures[glyphName(r)], Advance: advance}
+	}
+	return glyphs
+}
@@ -0,0 +1,75 @@
+package textures
+
+import (
+	"fmt"
+	"image"
+	"invaders/assets"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// shieldSlots are blank regions reserved for drawing the bases, packed into
+// the atlas by Load like the sprites, so bases batch with the draws around
+// them. There's one for each base of the layout with the most, each as big
+// as the largest base.
+var shieldSlots []*ebiten.Image
+
+// shieldImages caches the regions handed out by ShieldImage, so each keeps
+// its texture ID.
+var shieldImages = make(map[shieldRegion]*ebiten.Image)
+
+type shieldRegion struct {
+	slot int
+	size image.Point
+}
+
+func shieldSlotName(i int) string {
+	return fmt.Sprintf("shield:%d", i)
+}
+
+// decodeShieldSlots adds a blank image per shield slot, to be uploaded with
+// the sprites.
+func (l *loader) decodeShieldSlots() int {
+	count := 0
+	var size image.Point
+	for _, layout := range assets.ShieldLayouts {
+		bases := layout.Count
+		if len(layout.X) > 0 {
+			bases = len(layout.X)
+		}
+		count = max(count, bases)
+		size.X = max(size.X, layout.Width())
+		size.Y = max(size.Y, layout.Height())
+	}
+
+	for i := range count {
+		l.decoded = append(l.decoded, decodedImage{name: shieldSlotName(i), img: image.NewRGBA(image.Rectangle{Max: size})})
+	}
+	return count
+}
+
+// ShieldSlots returns how many bases can be drawn from the atlas at once.
+func ShieldSlots() int {
+	return len(shieldSlots)
+}
+
+// ShieldImage returns a width x height region of shield slot i for a base to
+// be written into and drawn from, or nil if the base doesn't fit the slot.
+func ShieldImage(slot, width, height int) *ebiten.Image {
+	if slot < 0 || slot >= len(shieldSlots) {
+		return nil
+	}
+	region := shieldRegion{slot: slot, size: image.Pt(width, height)}
+	if img, ok := shieldImages[region]; ok {
+		return img
+	}
+
+	bounds := shieldSlots[slot].Bounds()
+	if width > bounds.Dx() || height > bounds.Dy() {
+		return nil
+	}
+	img := shieldSlots[slot].SubImage(image.Rectangle{Min: bounds.Min, Max: bounds.Min.Add(region.size)}).(*ebiten.Image)
+	textureIDs[img] = TextureID(shieldSlots[slot])
+	shieldImages[region] = img
+	return img
+}
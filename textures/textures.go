@@ -0,0 +1,130 @@
+// Package textures uploads the sprites decoded by the assets package to the
+// GPU for drawing. It's kept apart from assets so the game rules, and the
+// leaderboard server that re-simulates them, don't need a graphics device.
+package textures
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"invaders/assets"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	images = make(map[string]*ebiten.Image)          // Uploaded sprites and sheets by asset path
+	frames = make(map[*assets.Sheet][]*ebiten.Image) // Frames cut from each sheet, made on first use
+)
+
+// Load uploads every sprite and sheet decoded by assets.Load, along with the
+// HUD glyphs and room for the shields. Call it after assets.Load and before
+// drawing anything.
+func Load() error {
+	l := &loader{textures: make(map[string]*ebiten.Image)}
+	images = make(map[string]*ebiten.Image)
+	frames = make(map[*assets.Sheet][]*ebiten.Image)
+	atlasPixels = nil
+	atlasRects = make(map[string]image.Rectangle)
+	textureIDs = make(map[*ebiten.Image]int)
+	shieldImages = make(map[shieldRegion]*ebiten.Image)
+
+	for _, filePath := range assets.ImagePaths() {
+		l.decoded = append(l.decoded, decodedImage{name: filePath, img: assets.Image(filePath)})
+	}
+	glyphAdvances := l.decodeGlyphs()
+	slots := l.decodeShieldSlots()
+	l.upload()
+
+	for _, filePath := range assets.ImagePaths() {
+		images[filePath] = l.textures[filePath]
+	}
+	HUDGlyphs = l.glyphs(glyphAdvances)
+	shieldSlots = make([]*ebiten.Image, slots)
+	for i := range shieldSlots {
+		shieldSlots[i] = l.textures[shieldSlotName(i)]
+	}
+
+	return errors.Join(l.errs...)
+}
+
+// loader collects every upload failure instead of stopping at the first.
+type loader struct {
+	errs     []error
+	decoded  []decodedImage
+	textures map[string]*ebiten.Image // Uploaded images by name, set by upload
+}
+
+// Sprite returns the texture a sprite is drawn from.
+func Sprite(sprite *assets.Sprite) *ebiten.Image {
+	return images[sprite.Path]
+}
+
+// Frame returns the texture frame i of a sheet is drawn from.
+func Frame(sheet *assets.Sheet, i int) *ebiten.Image {
+	sheetFrames, ok := frames[sheet]
+	if !ok {
+		spriteSheet := images[sheet.Path]
+
+		// Frame rects are relative to the sheet, which may sit anywhere in the atlas
+		origin := spriteSheet.Bounds().Min
+		sheetFrames = make([]*ebiten.Image, len(sheet.Frames))
+		for i, frame := range sheet.Frames {
+			sheetFrames[i] = spriteSheet.SubImage(frame.Rect.Add(origin)).(*ebiten.Image)
+			textureIDs[sheetFrames[i]] = TextureID(spriteSheet)
+		}
+		frames[sheet] = sheetFrames
+	}
+	return sheetFrames[i]
+}
+
+// Reload uploads the pixels of an image after assets.Reload has read them.
+// Images that changed size get a texture of their own, as they no longer fit
+// their place in the atlas.
+func Reload(filePath string) error {
+	target, ok := images[filePath]
+	img := assets.Image(filePath)
+	if !ok || img == nil {
+		return fmt.Errorf("%s: not a known image", filePath)
+	}
+
+	// Frames are cut again on next use, as the sheet may have been re-cut
+	for sheet, sheetFrames := range frames {
+		if sheet.Path == filePath {
+			for _, frame := range sheetFrames {
+				delete(textureIDs, frame)
+			}
+			delete(frames, sheet)
+		}
+	}
+
+	if img.Bounds().Size() != target.Bounds().Size() {
+		if _, inAtlas := atlasRects[filePath]; !inAtlas {
+			target.Deallocate()
+		}
+		delete(atlasRects, filePath)
+		delete(textureIDs, target)
+
+		resized := ebiten.NewImageFromImage(img)
+		textureIDs[resized] = nextTextureID()
+		images[filePath] = resized
+		return nil
+	}
+	target.WritePixels(img.Pix)
+
+	// Keep the debug copy of the atlas in step
+	if rect, ok := atlasRects[filePath]; ok && atlasPixels != nil {
+		draw.Draw(atlasPixels, rect, img, image.Point{}, draw.Src)
+	}
+	return nil
+}
+
+// nextTextureID returns an ID no loaded texture uses yet.
+func nextTextureID() int {
+	next := 0
+	for _, id := range textureIDs {
+		next = max(next, id+1)
+	}
+	return next
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"image/color"
+	"invaders/game"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type ThemeID int
+
+const (
+	ThemeOriginal ThemeID = iota
+	ThemeMonochrome
+	ThemeArcadeOverlay
+	ThemeFullColor
+	themeCount
+)
+
+// EntityKind says what a sprite is, so themes can color entities differently.
+type EntityKind int
+
+const (
+	EntitySquidAlien EntityKind = iota
+	EntityArmAlien
+	EntityFootAlien
+	EntityPlayer
+	EntityPlayerShot
+	EntityAlienShot
+	EntityBase
+	EntityUFO
+)
+
+// Playfield rows covered by the cellophane strips on the arcade cabinet
+const (
+	overlayRedBottom = 32  // UFO strip across the top of the screen
+	overlayGreenTop  = 176 // Shields and player down to the bottom
+)
+
+// ThemeBand tints sprites whose top edge lies in [Top, Bottom).
+type ThemeBand struct {
+	Top    int
+	Bottom int
+	Tint   color.RGBA
+}
+
+// Theme colors sprites as they are drawn. An entity tint wins over a band tint.
+type Theme struct {
+	Name       string
+	Monochrome bool // Neutralize the yellow-green sprite palette to grey before tinting
+	Bands      []ThemeBand
+	Entities   map[EntityKind]color.RGBA
+}
+
+var themes = [themeCount]Theme{
+	ThemeOriginal: {
+		Name: "Original",
+	},
+	ThemeMonochrome: {
+		Name:       "Monochrome",
+		Monochrome: true,
+	},
+	ThemeArcadeOverlay: {
+		Name:       "Arcade Overlay",
+		Monochrome: true,
+		Bands: []ThemeBand{
+			{Top: 0, Bottom: overlayRedBottom, Tint: color.RGBA{255, 60, 60, 255}},
+			{Top: overlayGreenTop, Bottom: game.Height, Tint: color.RGBA{60, 255, 90, 255}},
+		},
+	},
+	ThemeFullColor: {
+		Name:       "Full Color",
+		Monochrome: true,
+		Entities: map[EntityKind]color.RGBA{
+			EntitySquidAlien: {255, 90, 220, 255},
+			EntityArmAlien:   {90, 220, 255, 255},
+			EntityFootAlien:  {140, 255, 90, 255},
+			EntityPlayer:     {90, 255, 120, 255},
+			EntityPlayerShot: {255, 255, 200, 255},
+			EntityAlienShot:  {255, 160, 60, 255},
+			EntityBase:       {60, 220, 90, 255},
+			EntityUFO:        {255, 60, 60, 255},
+		},
+	},
+}
+
+func (id ThemeID) Theme() *Theme {
+	if id < 0 || id >= themeCount {
+		return &themes[ThemeOriginal]
+	}
+	return &themes[id]
+}
+
+// Apply sets the color scale for a sprite of the given kind whose top edge is at playfield y.
+func (t *Theme) Apply(colorScale *ebiten.ColorScale, kind EntityKind, y int) {
+	if t.Monochrome {
+		// The brightest palette entry is (208, 208, 88); lift blue to match
+		colorScale.Scale(1, 1, 208.0/88.0, 1)
+	}
+
+	if tint, ok := t.Entities[kind]; ok {
+		colorScale.ScaleWithColor(tint)
+		return
+	}
+	for _, band := range t.Bands {
+		if y >= band.Top && y < band.Bottom {
+			colorScale.ScaleWithColor(band.Tint)
+			return
+		}
+	}
+}
+
+func alienEntityKind(a game.AlienType) EntityKind {
+	switch a {
+	case game.SquidAlien:
+		return EntitySquidAlien
+	case game.ArmAlien:
+		return EntityArmAlien
+	default:
+		return EntityFootAlien
+	}
+}
@@ -43,9 +43,25 @@ func (t *TitleScene) Draw(screen *ebiten.Image) {
 	op2.GeoM.Translate(float64(subtitleX), float64(subtitleY))
 	op2.ColorScale.ScaleWithColor(color.RGBA{180, 180, 200, 255})
 	text.Draw(screen, subtitleText, t.subtitleFont, op2)
+
+	// Draw options hint
+	optionsText := "Press O for Options"
+	optionsBounds, _ := text.Measure(optionsText, t.subtitleFont, 0)
+	optionsX := (w - int(optionsBounds)) / 2
+	optionsY := subtitleY + 40
+
+	op3 := &text.DrawOptions{}
+	op3.GeoM.Translate(float64(optionsX), float64(optionsY))
+	op3.ColorScale.ScaleWithColor(color.RGBA{130, 130, 160, 255})
+	text.Draw(screen, optionsText, t.subtitleFont, op3)
 }
 
 func (t *TitleScene) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyO) {
+		t.sceneManager.TransitionTo(SceneOptions)
+		return nil
+	}
+
 	// Check for key presses
 	if ebiten.IsKeyPressed(ebiten.KeySpace) ||
 		ebiten.IsKeyPressed(ebiten.KeyEnter) ||
@@ -54,14 +70,14 @@ func (t *TitleScene) Update() error {
 		inpututil.IsKeyJustPressed(ebiten.KeyS) ||
 		inpututil.IsKeyJustPressed(ebiten.KeyD) ||
 		inpututil.IsKeyJustPressed(ebiten.KeyW) {
-		t.sceneManager.TransitionTo(SceneGame)
+		t.sceneManager.StartGame()
 		return nil
 	}
 
 	// Check for mouse clicks
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
 		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
-		t.sceneManager.TransitionTo(SceneGame)
+		t.sceneManager.StartGame()
 		return nil
 	}
 